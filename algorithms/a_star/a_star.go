@@ -2,16 +2,26 @@ package a_star
 
 import (
 	"container/heap"
-	"log"
 	"math"
-	"os"
-	"time"
+	"slices"
 
 	"algo/algorithms"
-	"algo/maze"
-	"github.com/pkg/errors"
+	"algo/algorithms/heuristics"
 )
 
+func init() {
+	algorithms.Register(algorithms.Algorithm{
+		ID:         1,
+		Name:       "a-star",
+		Title:      "A*",
+		Solve:      AStar,
+		Configure:  Configure,
+		Weighted:   true,
+		Heuristics: true,
+		Movements:  true,
+	})
+}
+
 // PriorityQueue реализует очередь приоритетов для узлов
 type PriorityQueue []*algorithms.Node
 
@@ -23,10 +33,14 @@ func (pq PriorityQueue) Less(i, j int) bool {
 
 func (pq PriorityQueue) Swap(i, j int) {
 	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].Index = i
+	pq[j].Index = j
 }
 
 func (pq *PriorityQueue) Push(x interface{}) {
+	n := len(*pq)
 	item := x.(*algorithms.Node)
+	item.Index = n
 	*pq = append(*pq, item)
 }
 
@@ -34,15 +48,12 @@ func (pq *PriorityQueue) Pop() interface{} {
 	old := *pq
 	n := len(old)
 	item := old[n-1]
+	old[n-1] = nil
+	item.Index = -1
 	*pq = old[0 : n-1]
 	return item
 }
 
-// heuristic возвращает эвристическое значение между двумя узлами
-func heuristic(a, b *algorithms.Node) int {
-	return int(math.Abs(float64(a.X-b.X)) + math.Abs(float64(a.Y-b.Y)))
-}
-
 // isInClosedList проверяет, находится ли узел в закрытом списке
 func isInClosedList(closedList map[[2]int]bool, node *algorithms.Node) bool {
 	_, found := closedList[[2]int{node.X, node.Y}]
@@ -59,14 +70,51 @@ func isInOpenList(openList map[[2]int]*algorithms.Node, node *algorithms.Node) b
 func reconstructPath(current *algorithms.Node) []algorithms.Node {
 	path := make([]algorithms.Node, 0)
 	for current != nil {
-		path = append([]algorithms.Node{*current}, path...)
+		path = append(path, *current)
 		current = current.Parent
 	}
+	slices.Reverse(path)
 	return path
 }
 
 // AStar алгоритм поиска кратчайшего пути
-func AStar(board [][]bool, startX, startY int, targets [][2]int) (int, []algorithms.Node) {
+func AStar(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	return BestFirst(board, startX, startY, targets, heuristics.Grid4.Moves, heuristics.Manhattan, func(g, h int) int { return g + h })
+}
+
+// weightScale знаменатель веса эвристики, позволяет сравнивать ключи взвешенного поиска в целых числах
+const weightScale = 1000
+
+// Configure возвращает A* с выбранными моделью перемещения, эвристикой и ключом g + weight*h. С весом больше 1 путь
+// может оказаться длиннее кратчайшего, но не более чем в weight раз, зато раскрывается меньше узлов. Вес учитывается
+// с точностью до тысячных
+func Configure(options algorithms.Options) algorithms.Solver {
+	movement := options.Movement
+	if len(movement.Moves) == 0 {
+		movement = heuristics.Grid4
+	}
+	h := options.Heuristic
+	if h == nil {
+		h = movement.Heuristic
+	}
+	hWeight := weightScale
+	if options.Weight > 0 {
+		hWeight = int(math.Round(options.Weight * weightScale))
+	}
+
+	return func(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+		return BestFirst(board, startX, startY, targets, movement.Moves, h, func(g, h int) int { return g*weightScale + h*hWeight })
+	}
+}
+
+// BestFirst поиск по первому наилучшему совпадению: из очереди извлекается узел с наименьшим ключом F = key(G, H),
+// где H — оценка h до ближайшей цели. Раскрытые узлы повторно не раскрываются. Стоимости ходов moves должны быть
+// целыми, ход по диагонали не срезает угол стены: обе клетки по сторонам от него должны быть свободны
+func BestFirst(board [][]bool, startX, startY int, targets [][2]int, moves []heuristics.Move, h heuristics.Heuristic, key func(g, h int) int) algorithms.Result {
+	if len(targets) == 0 {
+		return algorithms.NotFound(0)
+	}
+
 	openList := &PriorityQueue{}
 	heap.Init(openList)
 	closedList := make(map[[2]int]bool)
@@ -75,69 +123,45 @@ func AStar(board [][]bool, startX, startY int, targets [][2]int) (int, []algorit
 	openListMap := make(map[[2]int]*algorithms.Node)
 	openListMap[[2]int{startNode.X, startNode.Y}] = startNode
 
+	expanded := 0
 	for openList.Len() > 0 {
 		current := heap.Pop(openList).(*algorithms.Node)
+		expanded++
 		delete(openListMap, [2]int{current.X, current.Y})
 
 		for _, target := range targets {
 			if current.X == target[0] && current.Y == target[1] {
-				return current.G, reconstructPath(current)
+				return algorithms.Result{Dist: current.G, Path: reconstructPath(current), Expanded: expanded}
 			}
 		}
 
 		closedList[[2]int{current.X, current.Y}] = true
 
-		neighbors := [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
-		for _, dir := range neighbors {
-			neighbor := &algorithms.Node{X: current.X + dir[0], Y: current.Y + dir[1]}
+		for _, move := range moves {
+			neighbor := &algorithms.Node{X: current.X + move.DX, Y: current.Y + move.DY}
 			if !algorithms.IsValid(board, neighbor.X, neighbor.Y) || isInClosedList(closedList, neighbor) {
 				continue
 			}
+			if move.DX != 0 && move.DY != 0 && (!algorithms.IsValid(board, current.X+move.DX, current.Y) || !algorithms.IsValid(board, current.X, current.Y+move.DY)) {
+				continue
+			}
 
-			tentativeG := current.G + 1
+			tentativeG := current.G + int(move.Cost)
 			if !isInOpenList(openListMap, neighbor) {
-				neighbor.H = heuristic(neighbor, &algorithms.Node{X: targets[0][0], Y: targets[0][1]})
-				neighbor.F = tentativeG + neighbor.H
+				neighbor.H = heuristics.ToNearest(h, neighbor.X, neighbor.Y, targets)
+				neighbor.F = key(tentativeG, neighbor.H)
 				neighbor.G = tentativeG
 				neighbor.Parent = current
 				heap.Push(openList, neighbor)
 				openListMap[[2]int{neighbor.X, neighbor.Y}] = neighbor
-			} else if tentativeG < neighbor.G {
-				neighbor.G = tentativeG
-				neighbor.F = neighbor.G + neighbor.H
-				neighbor.Parent = current
-				openListMap[[2]int{neighbor.X, neighbor.Y}] = neighbor
+			} else if existing := openListMap[[2]int{neighbor.X, neighbor.Y}]; tentativeG < existing.G {
+				existing.G = tentativeG
+				existing.F = key(existing.G, existing.H)
+				existing.Parent = current
+				heap.Fix(openList, existing.Index)
 			}
 		}
 	}
 
-	return algorithms.PathNotFound, nil
-}
-
-func TestAStar() {
-	board, err := maze.ParseMaze(os.Getenv("MAZE_FILE_2"))
-	if err != nil {
-		log.Fatal(errors.Wrap(err, "failed to parse maze"))
-	}
-
-	startX, startY := 1, 0
-
-	boundaryCells := algorithms.GetBoundaryCells(board, startX, startY)
-	if len(boundaryCells) == 0 {
-		log.Println("Нет доступных выходных клеток на границе")
-		return
-	}
-
-	startTime := time.Now()
-	distance, path := AStar(board, startX, startY, boundaryCells)
-	endTime := time.Now()
-	log.Printf("Время работы: %d\n", endTime.Sub(startTime))
-
-	if distance != -1 {
-		log.Printf("Кратчайшее расстояние до выхода: %d\n", distance)
-		log.Println("Лабиринт с выделенным путем:")
-		algorithms.PrintBoard(board, path, boundaryCells, startX, startY)
-	} else {
-		log.Println("Путь до выхода не найден")
-	}
+	return algorithms.NotFound(expanded)
 }
@@ -0,0 +1,97 @@
+package a_star
+
+import (
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/heuristics"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+// TestWeightedTradesLengthForExpansions проверяет, что с ростом веса раскрывается меньше узлов,
+// а найденные пути удлиняются не больше, чем позволяет вес
+func TestWeightedTradesLengthForExpansions(t *testing.T) {
+	board, err := maze.GenerateRandom(300, 300, 0.3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := [][2]int{testboards.LastFreeCell(board)}
+
+	optimal := AStar(board, 0, 0, targets)
+	if optimal.Dist == algorithms.PathNotFound {
+		t.Fatal("path not found")
+	}
+
+	previous := optimal
+	for _, weight := range []float64{1.5, 3} {
+		result := Configure(algorithms.Options{Weight: weight})(board, 0, 0, targets)
+		if float64(result.Dist) > weight*float64(optimal.Dist) {
+			t.Errorf("weight %g: dist %d, optimal %d", weight, result.Dist, optimal.Dist)
+		}
+		if result.Expanded >= previous.Expanded {
+			t.Errorf("weight %g: expanded %d nodes, smaller weight expanded %d", weight, result.Expanded, previous.Expanded)
+		}
+		previous = result
+	}
+}
+
+// TestGrid8MatchesBFS сравнивает A* с ходами в восемь соседних клеток с обходом в ширину по тем же ходам
+func TestGrid8MatchesBFS(t *testing.T) {
+	solve := Configure(algorithms.Options{Movement: heuristics.Grid8})
+
+	for seed := int64(1); seed <= 20; seed++ {
+		board, err := maze.GenerateRandom(30, 30, 0.3, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		target := testboards.LastFreeCell(board)
+
+		dist := map[[2]int]int{{0, 0}: 0}
+		queue := [][2]int{{0, 0}}
+		for head := 0; head < len(queue); head++ {
+			current := queue[head]
+			for _, move := range heuristics.Grid8.Moves {
+				next := [2]int{current[0] + move.DX, current[1] + move.DY}
+				if _, seen := dist[next]; seen || !algorithms.IsValid(board, next[0], next[1]) ||
+					!algorithms.IsValid(board, next[0], current[1]) || !algorithms.IsValid(board, current[0], next[1]) {
+					continue
+				}
+				dist[next] = dist[current] + 1
+				queue = append(queue, next)
+			}
+		}
+
+		want, found := dist[target]
+		if !found {
+			want = algorithms.PathNotFound
+		}
+		if result := solve(board, 0, 0, [][2]int{target}); result.Dist != want {
+			t.Errorf("seed %d: dist %d, bfs %d", seed, result.Dist, want)
+		}
+	}
+}
+
+func BenchmarkAStarLabyrinth41x41(b *testing.B) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	testboards.Benchmark(b, AStar, board, 1, 0, algorithms.GetBoundaryCells(board, 1, 0))
+}
+
+func BenchmarkAStarLabyrinth41x41ManyTargets(b *testing.B) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41_many_targets.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	testboards.Benchmark(b, AStar, board, 1, 0, algorithms.GetBoundaryCells(board, 1, 0))
+}
+
+func BenchmarkAStarGenerated401x401(b *testing.B) {
+	testboards.BenchmarkGenerated401x401(b, AStar)
+}
+
+func BenchmarkAStarRandom1000x1000(b *testing.B) {
+	testboards.BenchmarkRandom1000x1000(b, AStar)
+}
@@ -0,0 +1,388 @@
+package alt
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/a_star"
+	"algo/algorithms/heuristics"
+)
+
+// Name имя алгоритма в реестре и метриках
+const Name = "alt"
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{
+		ID:    9,
+		Name:  Name,
+		Title: "ALT",
+		Solve: ALT,
+		Preprocess: func(board [][]bool) algorithms.Index {
+			return Preprocess(board, DefaultOptions)
+		},
+	})
+}
+
+// Strategy способ выбора ориентиров
+type Strategy string
+
+const (
+	// Farthest выбирает каждый следующий ориентир как клетку, самую далекую от уже выбранных
+	Farthest Strategy = "farthest"
+	// Avoid выбирает ориентиры в тех частях лабиринта, где текущая оценка хуже всего приближает расстояние
+	Avoid Strategy = "avoid"
+)
+
+// ParseStrategy разбирает имя стратегии, пустая строка означает стратегию по умолчанию
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(name) {
+	case "":
+		return DefaultOptions.Strategy, nil
+	case Farthest, Avoid:
+		return Strategy(name), nil
+	default:
+		return "", fmt.Errorf("unknown landmark strategy %q, expected %q or %q", name, Farthest, Avoid)
+	}
+}
+
+// MaxLandmarks наибольшее количество ориентиров, совпадает с ограничением в конфигурации лабиринта
+const MaxLandmarks = 64
+
+// Options параметры выбора ориентиров
+type Options struct {
+	Count    int      // Количество ориентиров
+	Strategy Strategy // Способ выбора ориентиров
+}
+
+// DefaultOptions параметры, с которыми алгоритм зарегистрирован в сервисе
+var DefaultOptions = Options{Count: 8, Strategy: Avoid}
+
+// unreachable расстояние в таблице ориентира до клетки, которая от него недостижима
+const unreachable = -1
+
+// Index ориентиры лабиринта и таблицы кратчайших расстояний от каждого из них до всех клеток.
+// По неравенству треугольника |d(L, a) - d(L, b)| не больше расстояния между a и b, поэтому максимум
+// таких разностей по ориентирам — допустимая и согласованная эвристика, обычно намного точнее Манхэттена
+type Index struct {
+	board     [][]bool
+	options   Options
+	landmarks [][2]int
+	dist      [][]int32 // dist[i][x*cols+y] — расстояние от i-го ориентира до клетки, unreachable — клетка недостижима
+	elapsed   time.Duration
+}
+
+// ALT выбирает ориентиры с параметрами по умолчанию и ищет путь. Для повторных запросов к одному лабиринту
+// выгоднее выбрать ориентиры один раз через Preprocess
+func ALT(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	return Preprocess(board, DefaultOptions).Solve(startX, startY, targets)
+}
+
+// Preprocess выбирает ориентиры и считает таблицы расстояний от них
+func Preprocess(board [][]bool, options Options) *Index {
+	startTime := time.Now()
+
+	index := &Index{board: cloneBoard(board), options: options}
+	index.selectLandmarks()
+
+	index.elapsed = time.Since(startTime)
+	return index
+}
+
+// Solve ищет путь алгоритмом A* с эвристикой по ориентирам
+func (index *Index) Solve(startX, startY int, targets [][2]int) algorithms.Result {
+	return a_star.BestFirst(index.board, startX, startY, targets, heuristics.Grid4.Moves, index, func(g, h int) int { return g + h })
+}
+
+// Update возвращает индекс для измененного лабиринта. Изменение одной клетки может изменить расстояния
+// во всем лабиринте, поэтому таблицы считаются заново. Ориентиры, которые остались свободными клетками,
+// сохраняются, вместо заложенных стенами выбираются новые
+func (index *Index) Update(board [][]bool) algorithms.Index {
+	if len(board) != len(index.board) || len(board[0]) != len(index.board[0]) {
+		return Preprocess(board, index.options)
+	}
+
+	startTime := time.Now()
+
+	updated := &Index{board: cloneBoard(board), options: index.options}
+	for _, landmark := range index.landmarks {
+		if !board[landmark[0]][landmark[1]] {
+			updated.addLandmark(landmark)
+		}
+	}
+	// Если все ориентиры остались свободными, искать область для новых не нужно
+	if len(updated.landmarks) < min(updated.options.Count, MaxLandmarks) {
+		updated.selectLandmarks()
+	}
+
+	updated.elapsed = time.Since(startTime)
+	return updated
+}
+
+// Name имя эвристики по ориентирам
+func (index *Index) Name() string { return "landmarks" }
+
+// Between возвращает наибольшую из оценок по ориентирам и расстояния по Манхэттену. Ориентир, от которого
+// недостижима хотя бы одна из клеток, ничего не сообщает о расстоянии между ними
+func (index *Index) Between(x1, y1, x2, y2 int) int {
+	cols := len(index.board[0])
+	a, b := x1*cols+y1, x2*cols+y2
+
+	best := heuristics.Manhattan.Between(x1, y1, x2, y2)
+	for _, dist := range index.dist {
+		if dist[a] == unreachable || dist[b] == unreachable {
+			continue
+		}
+		best = max(best, int(abs(dist[a]-dist[b])))
+	}
+	return best
+}
+
+// Landmarks возвращает выбранные ориентиры
+func (index *Index) Landmarks() [][2]int {
+	return slices.Clone(index.landmarks)
+}
+
+// Options возвращает параметры, с которыми выбраны ориентиры
+func (index *Index) Options() Options {
+	return index.options
+}
+
+// Elapsed возвращает время выбора ориентиров и расчета таблиц
+func (index *Index) Elapsed() time.Duration {
+	return index.elapsed
+}
+
+// selectLandmarks добавляет ориентиры выбранной стратегией, пока их меньше заданного количества.
+// Ориентиры выбираются в самой большой связной области лабиринта: в остальных областях оценка
+// остается Манхэттенской
+func (index *Index) selectLandmarks() {
+	count := min(index.options.Count, MaxLandmarks)
+	component := index.largestComponent()
+	if len(component) == 0 {
+		return
+	}
+
+	// Генератор зависит только от размера области, поэтому один и тот же лабиринт получает одни и те же ориентиры
+	rnd := rand.New(rand.NewSource(int64(len(component))))
+	for len(index.landmarks) < count {
+		var (
+			landmark [2]int
+			found    bool
+		)
+		switch index.options.Strategy {
+		case Farthest:
+			landmark, found = index.farthest(component)
+		default:
+			// Если оценка от корня уже точна во всей области, например в лабиринте без стен,
+			// ориентир выбирается как самая далекая клетка
+			if landmark, found = index.avoid(component[rnd.Intn(len(component))]); !found {
+				landmark, found = index.farthest(component)
+			}
+		}
+		if !found {
+			return
+		}
+		index.addLandmark(landmark)
+	}
+}
+
+// addLandmark добавляет ориентир и считает расстояния от него
+func (index *Index) addLandmark(landmark [2]int) {
+	dist, _ := index.bfs(landmark)
+	index.landmarks = append(index.landmarks, landmark)
+	index.dist = append(index.dist, dist)
+}
+
+// farthest возвращает клетку области, у которой расстояние до ближайшего ориентира наибольшее. Если ни один
+// ориентир не достигает области, первый ориентир в ней — клетка, самая далекая от первой клетки области
+func (index *Index) farthest(component [][2]int) ([2]int, bool) {
+	cols := len(index.board[0])
+
+	nearest := make([]int32, len(index.board)*cols)
+	for i := range nearest {
+		nearest[i] = math.MaxInt32
+	}
+	for _, dist := range index.dist {
+		for cell, d := range dist {
+			if d != unreachable {
+				nearest[cell] = min(nearest[cell], d)
+			}
+		}
+	}
+	if first := component[0]; nearest[first[0]*cols+first[1]] == math.MaxInt32 {
+		nearest, _ = index.bfs(first)
+	}
+
+	var (
+		best     [2]int
+		bestDist int32
+	)
+	for _, cell := range component {
+		if d := nearest[cell[0]*cols+cell[1]]; d > bestDist {
+			best, bestDist = cell, d
+		}
+	}
+	return best, bestDist > 0
+}
+
+// avoid реализует стратегию avoid (Goldberg, Harrelson): строит дерево кратчайших путей из корня и для каждой
+// клетки считает, насколько текущая оценка от корня меньше расстояния. Вес поддерева суммирует эти недооценки,
+// поддеревья с ориентирами не учитываются. Ориентир — лист, до которого ведет спуск от клетки с наибольшим весом
+// поддерева через ребенка с наибольшим весом
+func (index *Index) avoid(root [2]int) ([2]int, bool) {
+	cols := len(index.board[0])
+	dist, order := index.bfs(root)
+
+	parent := make([]int, len(dist))
+	for i := range parent {
+		parent[i] = -1
+	}
+	for _, cell := range order[1:] {
+		x, y := cell/cols, cell%cols
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if algorithms.IsValid(index.board, nx, ny) && dist[nx*cols+ny] == dist[cell]-1 {
+				parent[cell] = nx*cols + ny
+				break
+			}
+		}
+	}
+
+	isLandmark := make([]bool, len(dist))
+	for _, landmark := range index.landmarks {
+		isLandmark[landmark[0]*cols+landmark[1]] = true
+	}
+
+	// Клетки в порядке обхода в ширину, поэтому обратный порядок обрабатывает детей раньше родителей
+	size := make([]int, len(dist))
+	covered := make([]bool, len(dist)) // В поддереве есть ориентир
+	for i := len(order) - 1; i >= 0; i-- {
+		cell := order[i]
+		covered[cell] = covered[cell] || isLandmark[cell]
+		if covered[cell] {
+			size[cell] = 0
+		} else {
+			size[cell] += int(dist[cell]) - index.Between(root[0], root[1], cell/cols, cell%cols)
+		}
+		if p := parent[cell]; p != -1 {
+			covered[p] = covered[p] || covered[cell]
+			size[p] += size[cell]
+		}
+	}
+
+	current := -1
+	for _, cell := range order {
+		if !covered[cell] && (current == -1 || size[cell] > size[current]) {
+			current = cell
+		}
+	}
+	if current == -1 || size[current] == 0 {
+		return [2]int{}, false
+	}
+
+	for {
+		next := -1
+		x, y := current/cols, current%cols
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if !algorithms.IsValid(index.board, nx, ny) {
+				continue
+			}
+			if child := nx*cols + ny; parent[child] == current && !covered[child] && (next == -1 || size[child] > size[next]) {
+				next = child
+			}
+		}
+		if next == -1 {
+			return [2]int{current / cols, current % cols}, true
+		}
+		current = next
+	}
+}
+
+// largestComponent возвращает клетки самой большой связной области свободных клеток. Все области размечаются
+// одним обходом с общими массивами, поэтому время линейно по размеру лабиринта при любом количестве областей
+func (index *Index) largestComponent() [][2]int {
+	rows, cols := len(index.board), len(index.board[0])
+	seen := make([]bool, rows*cols)
+	queue := make([]int, 0, rows*cols)
+
+	var largestStart, largestEnd int
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			if index.board[x][y] || seen[x*cols+y] {
+				continue
+			}
+
+			start := len(queue)
+			seen[x*cols+y] = true
+			queue = append(queue, x*cols+y)
+			for i := start; i < len(queue); i++ {
+				cx, cy := queue[i]/cols, queue[i]%cols
+				for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+					nx, ny := cx+dir[0], cy+dir[1]
+					if algorithms.IsValid(index.board, nx, ny) && !seen[nx*cols+ny] {
+						seen[nx*cols+ny] = true
+						queue = append(queue, nx*cols+ny)
+					}
+				}
+			}
+			if len(queue)-start > largestEnd-largestStart {
+				largestStart, largestEnd = start, len(queue)
+			}
+		}
+	}
+
+	component := make([][2]int, 0, largestEnd-largestStart)
+	for _, cell := range queue[largestStart:largestEnd] {
+		component = append(component, [2]int{cell / cols, cell % cols})
+	}
+	return component
+}
+
+// bfs считает расстояния от клетки до всех клеток лабиринта и возвращает достижимые клетки в порядке обхода
+func (index *Index) bfs(from [2]int) ([]int32, []int) {
+	rows, cols := len(index.board), len(index.board[0])
+	dist := make([]int32, rows*cols)
+	for i := range dist {
+		dist[i] = unreachable
+	}
+
+	start := from[0]*cols + from[1]
+	dist[start] = 0
+	order := []int{start}
+	for i := 0; i < len(order); i++ {
+		cell := order[i]
+		x, y := cell/cols, cell%cols
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if !algorithms.IsValid(index.board, nx, ny) {
+				continue
+			}
+			if next := nx*cols + ny; dist[next] == unreachable {
+				dist[next] = dist[cell] + 1
+				order = append(order, next)
+			}
+		}
+	}
+
+	return dist, order
+}
+
+func cloneBoard(board [][]bool) [][]bool {
+	clone := make([][]bool, len(board))
+	for i, row := range board {
+		clone[i] = slices.Clone(row)
+	}
+	return clone
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
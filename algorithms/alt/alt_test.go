@@ -0,0 +1,178 @@
+package alt
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/a_star"
+	"algo/maze"
+)
+
+// TestLandmarksAreAdmissible проверяет, что оценка по ориентирам не превышает расстояние между клетками
+// и отличается от оценки соседней клетки не больше чем на единицу
+func TestLandmarksAreAdmissible(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, strategy := range []Strategy{Farthest, Avoid} {
+		for i := 0; i < 20; i++ {
+			board, err := maze.GenerateRandom(5+rnd.Intn(30), 5+rnd.Intn(30), rnd.Float64()*0.4, rnd.Int63())
+			if err != nil {
+				t.Fatal(err)
+			}
+			index := Preprocess(board, Options{Count: 4, Strategy: strategy})
+
+			from := [2]int{rnd.Intn(len(board)), rnd.Intn(len(board[0]))}
+			if board[from[0]][from[1]] {
+				continue
+			}
+			dist, _ := index.bfs(from)
+			cols := len(board[0])
+			for x := range board {
+				for y := range board[x] {
+					d := dist[x*cols+y]
+					if d == unreachable {
+						continue
+					}
+					if h := index.Between(x, y, from[0], from[1]); h > int(d) {
+						t.Fatalf("%s: estimate %d from (%d, %d) to %v exceeds distance %d", strategy, h, x, y, from, d)
+					}
+					if x+1 < len(board) && !board[x+1][y] {
+						if diff := index.Between(x, y, from[0], from[1]) - index.Between(x+1, y, from[0], from[1]); diff > 1 || diff < -1 {
+							t.Fatalf("%s: estimate changes by %d between neighbors (%d, %d) and (%d, %d)", strategy, diff, x, y, x+1, y)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestStrategiesSelectDistinctLandmarks проверяет, что обе стратегии выбирают заданное количество разных свободных клеток
+func TestStrategiesSelectDistinctLandmarks(t *testing.T) {
+	board, err := maze.Generate(41, 41, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strategy := range []Strategy{Farthest, Avoid} {
+		landmarks := Preprocess(board, Options{Count: 6, Strategy: strategy}).Landmarks()
+		if len(landmarks) != 6 {
+			t.Fatalf("%s: got %d landmarks, want 6", strategy, len(landmarks))
+		}
+		for i, landmark := range landmarks {
+			if board[landmark[0]][landmark[1]] {
+				t.Errorf("%s: landmark %v is wall", strategy, landmark)
+			}
+			if slices.Contains(landmarks[:i], landmark) {
+				t.Errorf("%s: landmark %v selected twice", strategy, landmark)
+			}
+		}
+	}
+
+	// В пустом лабиринте Манхэттен точен, и ориентиры выбираются как самые далекие клетки
+	empty := make([][]bool, 10)
+	for i := range empty {
+		empty[i] = make([]bool, 10)
+	}
+	if got := len(Preprocess(empty, Options{Count: 3, Strategy: Avoid}).Landmarks()); got != 3 {
+		t.Errorf("empty maze: got %d landmarks, want 3", got)
+	}
+}
+
+// TestLandmarksReduceExpansions проверяет, что ALT находит путь той же длины, что и A*, раскрывая меньше узлов
+func TestLandmarksReduceExpansions(t *testing.T) {
+	board, err := maze.Generate(201, 201, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := [][2]int{{199, 200}}
+
+	reference := a_star.AStar(board, 1, 0, targets)
+	for _, strategy := range []Strategy{Farthest, Avoid} {
+		result := Preprocess(board, Options{Count: 8, Strategy: strategy}).Solve(1, 0, targets)
+		if result.Dist != reference.Dist {
+			t.Fatalf("%s: dist %d, A* dist %d", strategy, result.Dist, reference.Dist)
+		}
+		if result.Expanded >= reference.Expanded {
+			t.Errorf("%s: expanded %d nodes, A* expanded %d", strategy, result.Expanded, reference.Expanded)
+		}
+	}
+}
+
+// TestUpdateKeepsFreeLandmarks проверяет, что обновление сохраняет свободные ориентиры и заменяет заложенные стеной
+func TestUpdateKeepsFreeLandmarks(t *testing.T) {
+	board, err := maze.Generate(41, 41, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := Preprocess(board, Options{Count: 4, Strategy: Farthest})
+	landmarks := index.Landmarks()
+
+	board[landmarks[0][0]][landmarks[0][1]] = true
+	updated := index.Update(board).(*Index)
+	got := updated.Landmarks()
+	if len(got) != len(landmarks) {
+		t.Fatalf("got %d landmarks after update, want %d", len(got), len(landmarks))
+	}
+	if slices.Contains(got, landmarks[0]) {
+		t.Errorf("landmark %v is wall after update", landmarks[0])
+	}
+	for _, landmark := range landmarks[1:] {
+		if !slices.Contains(got, landmark) {
+			t.Errorf("free landmark %v was not kept", landmark)
+		}
+	}
+	if index.Landmarks()[0] != landmarks[0] {
+		t.Error("update must not change the original index")
+	}
+
+	start, targets := [2]int{1, 0}, [][2]int{{39, 40}}
+	if got, want := updated.Solve(start[0], start[1], targets).Dist, a_star.AStar(board, start[0], start[1], targets).Dist; got != want || got == algorithms.PathNotFound {
+		t.Errorf("updated index found dist %d, A* %d", got, want)
+	}
+}
+
+// TestManyComponents проверяет, что ориентиры выбираются в самой большой области, а время предобработки
+// не растет с количеством областей: лабиринт 400x400 содержит около 40 тысяч изолированных клеток
+func TestManyComponents(t *testing.T) {
+	const size = 400
+	board := make([][]bool, size)
+	for x := range board {
+		board[x] = make([]bool, size)
+		for y := range board[x] {
+			// Строки 0-8 — одна большая область, ниже — изолированные клетки
+			board[x][y] = x == 9 || (x > 9 && (x%2 == 1 || y%2 == 1))
+		}
+	}
+
+	index := Preprocess(board, Options{Count: 8, Strategy: Avoid})
+	if len(index.Landmarks()) != 8 {
+		t.Fatalf("got %d landmarks, want 8", len(index.Landmarks()))
+	}
+	for _, landmark := range index.Landmarks() {
+		if landmark[0] >= 9 {
+			t.Errorf("landmark %v is outside the largest component", landmark)
+		}
+	}
+	if index.Elapsed() > 2*time.Second {
+		t.Errorf("preprocessing took %s", index.Elapsed())
+	}
+
+	// Обновление, при котором все ориентиры остаются свободными, не ищет области заново
+	board[size-2][0] = true
+	if updated := index.Update(board).(*Index); !slices.Equal(updated.Landmarks(), index.Landmarks()) || updated.Elapsed() > time.Second {
+		t.Errorf("update changed landmarks or took %s", updated.Elapsed())
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	if strategy, err := ParseStrategy(""); err != nil || strategy != DefaultOptions.Strategy {
+		t.Errorf("ParseStrategy(\"\") = %q, %v", strategy, err)
+	}
+	if _, err := ParseStrategy("random"); err == nil {
+		t.Error("unknown strategy must be rejected")
+	}
+}
@@ -0,0 +1,285 @@
+package ara_star
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/heuristics"
+)
+
+// Name имя алгоритма в реестре и метриках
+const Name = "ara-star"
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{ID: 7, Name: Name, Title: "ARA*", Solve: ARAStar, Approximate: true, TimeBounded: true})
+}
+
+// Статистика, которую ARA* сообщает в Result.Stats
+const (
+	StatSolutions     = "solutions"      // Количество найденных решений
+	StatBoundPermille = "bound_permille" // Граница субоптимальности последнего решения в тысячных
+)
+
+// Options параметры поиска
+type Options struct {
+	InitialWeight float64       // Вес эвристики первого поиска
+	WeightStep    float64       // Уменьшение веса после каждого найденного решения
+	Budget        time.Duration // Время на улучшение решения, 0 — без ограничения
+}
+
+// DefaultOptions параметры, с которыми алгоритм зарегистрирован в сервисе
+var DefaultOptions = Options{InitialWeight: 3, WeightStep: 0.5, Budget: 50 * time.Millisecond}
+
+// Solution очередное решение. Dist не больше длины кратчайшего пути, умноженной на Bound
+type Solution struct {
+	algorithms.Result
+	Weight  float64       // Вес эвристики, с которым найдено решение
+	Bound   float64       // Доказанная граница субоптимальности, 1 означает кратчайший путь
+	Elapsed time.Duration // Время от начала поиска
+}
+
+// item элемент очереди. Улучшение расстояния до клетки добавляет новый элемент, устаревшие элементы
+// пропускаются при извлечении. При смене веса очередь строится заново
+type item struct {
+	cell int
+	g    int
+	f    float64
+}
+
+// PriorityQueue реализует очередь приоритетов по ключу f, при равенстве первым идет элемент с большим g
+type PriorityQueue []item
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+
+func (pq PriorityQueue) Less(i, j int) bool {
+	if pq[i].f != pq[j].f {
+		return pq[i].f < pq[j].f
+	}
+	return pq[i].g > pq[j].g
+}
+
+func (pq PriorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *PriorityQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(item))
+}
+
+func (pq *PriorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	it := old[n-1]
+	*pq = old[0 : n-1]
+	return it
+}
+
+// Состояния клетки в текущем поиске
+const (
+	unseen       = iota
+	open         // Клетка в очереди
+	closed       // Клетка раскрыта в текущем поиске
+	inconsistent // Расстояние до раскрытой клетки улучшилось, она будет раскрыта в следующем поиске
+)
+
+// checkEvery количество раскрытий между проверками бюджета времени
+const checkEvery = 256
+
+// ARAStar ищет путь с параметрами по умолчанию и возвращает лучшее решение, найденное за бюджет времени
+func ARAStar(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	return Search(board, startX, startY, targets, DefaultOptions, nil)
+}
+
+// Search реализует Anytime Repairing A*. Первый поиск — взвешенный A* с ключом g + w*h, который быстро находит путь
+// не длиннее w кратчайших. Затем вес уменьшается, и каждый следующий поиск продолжает предыдущий: он раскрывает
+// только клетки, расстояние до которых улучшилось, а не начинает заново. После каждого поиска граница субоптимальности
+// уточняется как длина пути, деленная на наименьшее g + h среди нераскрытых клеток.
+//
+// emit вызывается для каждого решения, которое короче предыдущего или имеет меньшую границу, поиск прекращается,
+// если emit вернул false. Бюджет ограничивает только улучшение: первое решение ищется до конца, чтобы клиент
+// всегда получил путь. Поиск также завершается, когда граница достигает 1
+func Search(board [][]bool, startX, startY int, targets [][2]int, options Options, emit func(Solution) bool) algorithms.Result {
+	startTime := time.Now()
+	if len(targets) == 0 || !algorithms.IsValid(board, startX, startY) {
+		return algorithms.NotFound(0)
+	}
+
+	rows, cols := len(board), len(board[0])
+	isTarget := make([]bool, rows*cols)
+	for _, target := range targets {
+		if algorithms.IsValid(board, target[0], target[1]) {
+			isTarget[target[0]*cols+target[1]] = true
+		}
+	}
+
+	// Расстояние по Манхэттену до ближайшей цели, допустимая и согласованная эвристика
+	h := func(cell int) int {
+		return heuristics.ToNearest(heuristics.Manhattan, cell/cols, cell%cols, targets)
+	}
+
+	g := make([]int, rows*cols)
+	parent := make([]int, rows*cols)
+	state := make([]uint8, rows*cols)
+	for i := range g {
+		g[i] = -1
+		parent[i] = -1
+	}
+
+	var (
+		queue     PriorityQueue
+		weight    = max(options.InitialWeight, 1)
+		goal      = -1 // Достигнутая цель с наименьшим расстоянием
+		expanded  int
+		last      algorithms.Result
+		lastBound float64
+		found     int
+	)
+	key := func(cell int) float64 {
+		return float64(g[cell]) + weight*float64(h(cell))
+	}
+	push := func(cell int) {
+		state[cell] = open
+		heap.Push(&queue, item{cell: cell, g: g[cell], f: key(cell)})
+	}
+	// top удаляет устаревшие элементы и возвращает наименьший ключ в очереди
+	top := func() (item, bool) {
+		for queue.Len() > 0 {
+			it := queue[0]
+			if state[it.cell] == open && it.g == g[it.cell] {
+				return it, true
+			}
+			heap.Pop(&queue)
+		}
+		return item{}, false
+	}
+	outOfBudget := func() bool {
+		return found > 0 && options.Budget > 0 && time.Since(startTime) > options.Budget
+	}
+
+	start := startX*cols + startY
+	g[start] = 0
+	push(start)
+
+	for {
+		// Поиск с текущим весом: раскрываем клетки, пока ключ лучшей из них меньше длины найденного пути
+		stopped := false
+		for {
+			it, ok := top()
+			if !ok || (goal != -1 && it.f >= float64(g[goal])) {
+				break
+			}
+			if expanded%checkEvery == 0 && outOfBudget() {
+				stopped = true
+				break
+			}
+
+			heap.Pop(&queue)
+			state[it.cell] = closed
+			expanded++
+			if isTarget[it.cell] && (goal == -1 || g[it.cell] < g[goal]) {
+				goal = it.cell
+			}
+
+			x, y := it.cell/cols, it.cell%cols
+			for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+				nx, ny := x+dir[0], y+dir[1]
+				if !algorithms.IsValid(board, nx, ny) {
+					continue
+				}
+
+				neighbor := nx*cols + ny
+				if g[neighbor] != -1 && g[neighbor] <= it.g+1 {
+					continue
+				}
+				g[neighbor] = it.g + 1
+				parent[neighbor] = it.cell
+				if isTarget[neighbor] && (goal == -1 || g[neighbor] < g[goal]) {
+					goal = neighbor
+				}
+
+				switch state[neighbor] {
+				case closed, inconsistent:
+					state[neighbor] = inconsistent
+				default:
+					push(neighbor)
+				}
+			}
+		}
+
+		if goal == -1 {
+			return algorithms.NotFound(expanded)
+		}
+		if stopped {
+			break
+		}
+
+		// Расстояние до клетки не меньше расстояния до ее родителя плюс один, поэтому путь по родителям
+		// может оказаться короче g цели, если расстояние до родителя улучшилось после выбора родителя
+		path := reconstructPath(parent, goal, cols)
+		dist := len(path) - 1
+
+		// Граница: кратчайший путь не короче наименьшего g + h среди клеток, которые еще могут его улучшить
+		lower := math.MaxInt
+		for cell, s := range state {
+			if s == open || s == inconsistent {
+				lower = min(lower, g[cell]+h(cell))
+			}
+		}
+		bound := 1.0
+		if lower < dist {
+			bound = min(weight, float64(dist)/float64(lower))
+		}
+
+		// Поиск с меньшим весом часто находит тот же путь с той же границей, такое решение не сообщается
+		if found == 0 || dist < last.Dist || bound < lastBound {
+			found++
+			lastBound = bound
+			last = algorithms.Result{
+				Dist:     dist,
+				Path:     path,
+				Expanded: expanded,
+				Stats:    map[string]int{StatSolutions: found, StatBoundPermille: int(math.Ceil(bound * 1000))},
+			}
+			if emit != nil && !emit(Solution{Result: last, Weight: weight, Bound: bound, Elapsed: time.Since(startTime)}) {
+				break
+			}
+		}
+		if bound <= 1 || outOfBudget() {
+			break
+		}
+
+		// Следующий поиск с меньшим весом продолжает текущий: несогласованные клетки возвращаются в очередь,
+		// ключи всех клеток очереди пересчитываются, раскрытые клетки снова могут быть раскрыты
+		weight = max(1, weight-max(options.WeightStep, 0.1))
+		queue = queue[:0]
+		for cell, s := range state {
+			switch s {
+			case open, inconsistent:
+				queue = append(queue, item{cell: cell, g: g[cell], f: key(cell)})
+				state[cell] = open
+			case closed:
+				state[cell] = unseen
+			}
+		}
+		heap.Init(&queue)
+	}
+
+	last.Expanded = expanded
+	return last
+}
+
+// reconstructPath восстанавливает путь от стартовой клетки до цели
+func reconstructPath(parent []int, goal, cols int) []algorithms.Node {
+	var path []algorithms.Node
+	for cell := goal; cell != -1; cell = parent[cell] {
+		path = append(path, algorithms.Node{X: cell / cols, Y: cell % cols})
+	}
+	slices.Reverse(path)
+	for i := range path {
+		path[i].G = i
+	}
+	return path
+}
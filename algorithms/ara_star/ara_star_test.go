@@ -0,0 +1,113 @@
+package ara_star
+
+import (
+	"math/rand"
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/a_star"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+// TestSolutionsRespectBounds проверяет, что каждое решение не длиннее границы, решения улучшаются,
+// а без ограничения времени последнее решение кратчайшее
+func TestSolutionsRespectBounds(t *testing.T) {
+	board, err := maze.GenerateRandom(200, 200, 0.3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	options := Options{InitialWeight: 3, WeightStep: 0.5}
+	for range 20 {
+		start, _ := testboards.RandomFreeCell(rnd, board)
+		target, _ := testboards.RandomFreeCell(rnd, board)
+		targets := [][2]int{target}
+		optimal := a_star.AStar(board, start[0], start[1], targets).Dist
+
+		var solutions []Solution
+		result := Search(board, start[0], start[1], targets, options, func(solution Solution) bool {
+			solutions = append(solutions, solution)
+			return true
+		})
+
+		if optimal == algorithms.PathNotFound {
+			if result.Dist != algorithms.PathNotFound || len(solutions) != 0 {
+				t.Fatalf("found path %d from %v to %v, a-star found none", result.Dist, start, target)
+			}
+			continue
+		}
+
+		for i, solution := range solutions {
+			if float64(solution.Dist) > solution.Bound*float64(optimal) || solution.Bound > solution.Weight {
+				t.Errorf("solution %d from %v to %v: dist %d, bound %.3f, weight %.1f, optimal %d",
+					i, start, target, solution.Dist, solution.Bound, solution.Weight, optimal)
+			}
+			if len(solution.Path) != solution.Dist+1 {
+				t.Errorf("solution %d: path has %d nodes for dist %d", i, len(solution.Path), solution.Dist)
+			}
+			if i > 0 && (solution.Dist > solutions[i-1].Dist || solution.Bound > solutions[i-1].Bound ||
+				solution.Dist == solutions[i-1].Dist && solution.Bound == solutions[i-1].Bound) {
+				t.Errorf("solution %d does not improve previous: %d/%.3f after %d/%.3f",
+					i, solution.Dist, solution.Bound, solutions[i-1].Dist, solutions[i-1].Bound)
+			}
+		}
+
+		last := solutions[len(solutions)-1]
+		if last.Bound != 1 || result.Dist != optimal {
+			t.Errorf("last solution from %v to %v: dist %d, bound %.3f, optimal %d", start, target, result.Dist, last.Bound, optimal)
+		}
+		if result.Stats[StatSolutions] != len(solutions) || result.Stats[StatBoundPermille] != 1000 {
+			t.Errorf("stats %v for %d solutions", result.Stats, len(solutions))
+		}
+	}
+}
+
+func TestEmitStopsSearch(t *testing.T) {
+	board, err := maze.Generate(201, 201, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	result := Search(board, 1, 0, [][2]int{{199, 200}}, Options{InitialWeight: 5, WeightStep: 0.5}, func(Solution) bool {
+		calls++
+		return false
+	})
+	if calls != 1 || result.Stats[StatSolutions] != 1 {
+		t.Errorf("emit called %d times, stats %v, want one solution", calls, result.Stats)
+	}
+}
+
+func TestBudgetKeepsFirstSolution(t *testing.T) {
+	board, err := maze.Generate(301, 301, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Бюджет истекает сразу, но первое решение все равно должно быть найдено
+	result := Search(board, 1, 0, [][2]int{{299, 300}}, Options{InitialWeight: 3, WeightStep: 0.5, Budget: 1}, nil)
+	if result.Dist == algorithms.PathNotFound || len(result.Path) != result.Dist+1 {
+		t.Fatalf("dist %d, path has %d nodes", result.Dist, len(result.Path))
+	}
+	if result.Stats[StatSolutions] != 1 {
+		t.Errorf("found %d solutions with exhausted budget, want 1", result.Stats[StatSolutions])
+	}
+}
+
+func BenchmarkARAStarFirstSolutionRandom1000x1000(b *testing.B) {
+	board, err := maze.GenerateRandom(1000, 1000, 0.25, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+
+	var result algorithms.Result
+	for i := 0; i < b.N; i++ {
+		result = Search(board, 0, 0, [][2]int{{999, 999}}, Options{InitialWeight: 3, WeightStep: 0.5}, func(Solution) bool {
+			return false
+		})
+	}
+	b.ReportMetric(float64(result.Expanded), "expanded/op")
+}
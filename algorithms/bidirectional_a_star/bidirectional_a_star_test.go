@@ -0,0 +1,173 @@
+package bidirectional_a_star
+
+import (
+	"math/rand"
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/a_star"
+	"algo/algorithms/heuristics"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+// TestExpansionsComparedToAStar сравнивает суммарное количество раскрытых узлов с A* на задачах с одной целью.
+// Между случайными клетками встречный поиск раскрывает меньше узлов. От входа до выхода идеального лабиринта
+// оба поиска обходят почти весь лабиринт, и встречный допускается раскрыть не больше чем на 5% больше узлов
+func TestExpansionsComparedToAStar(t *testing.T) {
+	randomPairs := func(board [][]bool) [][2][2]int {
+		rnd := rand.New(rand.NewSource(1))
+		pairs := make([][2][2]int, 30)
+		for i := range pairs {
+			start, _ := testboards.RandomFreeCell(rnd, board)
+			target, _ := testboards.RandomFreeCell(rnd, board)
+			pairs[i] = [2][2]int{start, target}
+		}
+		return pairs
+	}
+
+	labyrinth, err := maze.Generate(201, 201, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	random, err := maze.GenerateRandom(300, 300, 0.2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := maze.GenerateRandom(300, 300, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := maze.Generate(401, 401, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		board [][]bool
+		pairs [][2][2]int
+		ratio float64 // Наибольшее допустимое отношение к количеству узлов, раскрытых A*
+	}{
+		{name: "labyrinth", board: labyrinth, pairs: randomPairs(labyrinth), ratio: 1},
+		{name: "random", board: random, pairs: randomPairs(random), ratio: 1},
+		{name: "open", board: open, pairs: randomPairs(open), ratio: 0.5},
+		{name: "labyrinth corner to corner", board: large, pairs: [][2][2]int{{{1, 0}, {399, 400}}}, ratio: 1.05},
+	} {
+		var expanded, expandedAStar int
+		for _, pair := range test.pairs {
+			start, targets := pair[0], [][2]int{pair[1]}
+
+			result := BidirectionalAStar(test.board, start[0], start[1], targets)
+			reference := a_star.AStar(test.board, start[0], start[1], targets)
+			if result.Dist != reference.Dist {
+				t.Fatalf("%s: dist %d from %v to %v, a-star %d", test.name, result.Dist, start, pair[1], reference.Dist)
+			}
+			expanded += result.Expanded
+			expandedAStar += reference.Expanded
+		}
+
+		if float64(expanded) >= test.ratio*float64(expandedAStar) {
+			t.Errorf("%s: expanded %d nodes, a-star %d, want less than %g of it", test.name, expanded, expandedAStar, test.ratio)
+		}
+	}
+}
+
+// distances возвращает расстояния от клетки до всех клеток доски, -1 — клетка недостижима
+func distances(board [][]bool, from [2]int) [][]int {
+	dist := make([][]int, len(board))
+	for i := range dist {
+		dist[i] = make([]int, len(board[i]))
+		for j := range dist[i] {
+			dist[i][j] = -1
+		}
+	}
+
+	dist[from[0]][from[1]] = 0
+	queue := [][2]int{from}
+	for head := 0; head < len(queue); head++ {
+		x, y := queue[head][0], queue[head][1]
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if algorithms.IsValid(board, nx, ny) && dist[nx][ny] == -1 {
+				dist[nx][ny] = dist[x][y] + 1
+				queue = append(queue, [2]int{nx, ny})
+			}
+		}
+	}
+	return dist
+}
+
+// TestLabyrinthLowerBound проверяет, почему встречный поиск не раскрывает заметно меньше узлов, чем A*, от входа
+// до выхода лабиринта из репозитория. Любой точный встречный поиск с эвристиками к противоположным концам раскрывает
+// хотя бы одну клетку каждой пары (u, v), для которой fПрямой(u), fОбратный(v) и gПрямой(u) + gОбратный(v) + 1
+// меньше длины кратчайшего пути C. Наименьшее вершинное покрытие графа таких пар равно наибольшему паросочетанию
+// и является нижней границей раскрытий. С эвристикой Манхэттена она меньше числа узлов, раскрытых A*, всего на 2%
+func TestLabyrinthLowerBound(t *testing.T) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, target := [2]int{1, 0}, [2]int{39, 40}
+
+	fromStart, toTarget := distances(board, start), distances(board, target)
+	optimal := fromStart[target[0]][target[1]]
+
+	// Клетки, которые могут быть раскрыты прямым и обратным поиском, с их расстояниями от начала направления
+	var forwardG, backwardG []int
+	for i, row := range board {
+		for j := range row {
+			if fromStart[i][j] == -1 {
+				continue
+			}
+			if fromStart[i][j]+heuristics.Manhattan.Between(i, j, target[0], target[1]) < optimal {
+				forwardG = append(forwardG, fromStart[i][j])
+			}
+			if toTarget[i][j]+heuristics.Manhattan.Between(i, j, start[0], start[1]) < optimal {
+				backwardG = append(backwardG, toTarget[i][j])
+			}
+		}
+	}
+
+	// Наибольшее паросочетание алгоритмом Куна
+	match := make([]int, len(backwardG))
+	for i := range match {
+		match[i] = -1
+	}
+	var augment func(u int, seen []bool) bool
+	augment = func(u int, seen []bool) bool {
+		for v, g := range backwardG {
+			if seen[v] || forwardG[u]+g+1 >= optimal {
+				continue
+			}
+			seen[v] = true
+			if match[v] == -1 || augment(match[v], seen) {
+				match[v] = u
+				return true
+			}
+		}
+		return false
+	}
+	bound := 0
+	for u := range forwardG {
+		if augment(u, make([]bool, len(backwardG))) {
+			bound++
+		}
+	}
+
+	result := BidirectionalAStar(board, start[0], start[1], [][2]int{target})
+	reference := a_star.AStar(board, start[0], start[1], [][2]int{target})
+	if result.Dist != optimal || reference.Dist != optimal {
+		t.Fatalf("dist %d, a-star %d, bfs %d", result.Dist, reference.Dist, optimal)
+	}
+	if result.Expanded < bound {
+		t.Errorf("expanded %d nodes, below the lower bound %d", result.Expanded, bound)
+	}
+	if float64(bound) < 0.97*float64(reference.Expanded) {
+		t.Errorf("lower bound %d is well below a-star %d, bidirectional search could expand fewer nodes", bound, reference.Expanded)
+	}
+}
+
+func BenchmarkBidirectionalAStarGenerated401x401(b *testing.B) {
+	testboards.BenchmarkGenerated401x401(b, BidirectionalAStar)
+}
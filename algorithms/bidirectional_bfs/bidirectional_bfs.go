@@ -0,0 +1,104 @@
+package bidirectional_bfs
+
+import (
+	"math"
+	"slices"
+
+	"algo/algorithms"
+)
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{ID: 5, Name: "bi-bfs", Title: "Bidirectional BFS", Solve: BidirectionalBFS})
+}
+
+// Направления поиска
+const (
+	forward  = 0 // От старта к целям
+	backward = 1 // От всех целей одновременно к старту
+)
+
+// BidirectionalBFS ищет кратчайший путь обходом в ширину одновременно от старта и от всех целей.
+// За шаг раскрывается целиком очередной уровень меньшего фронта. Если на уровне фронты встретились,
+// кратчайший путь проходит через одну из встреч этого уровня, поэтому выбирается лучшая из них.
+// В лабиринте от входа до выхода фронты обходят почти весь лабиринт, и узлов раскрывается не меньше, чем у A*
+func BidirectionalBFS(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	if len(targets) == 0 || !algorithms.IsValid(board, startX, startY) {
+		return algorithms.NotFound(0)
+	}
+
+	rows, cols := len(board), len(board[0])
+	var dist, parent [2][]int
+	for side := range dist {
+		dist[side] = make([]int, rows*cols)
+		parent[side] = make([]int, rows*cols)
+		for i := range dist[side] {
+			dist[side][i] = -1
+			parent[side][i] = -1
+		}
+	}
+
+	var frontier [2][]int
+	start := startX*cols + startY
+	dist[forward][start] = 0
+	frontier[forward] = []int{start}
+	for _, target := range targets {
+		cell := target[0]*cols + target[1]
+		if algorithms.IsValid(board, target[0], target[1]) && dist[backward][cell] == -1 {
+			dist[backward][cell] = 0
+			frontier[backward] = append(frontier[backward], cell)
+		}
+	}
+
+	if dist[backward][start] == 0 {
+		return algorithms.Result{Dist: 0, Path: []algorithms.Node{{X: startX, Y: startY}}, Expanded: 1}
+	}
+
+	best, meet := math.MaxInt, [2]int{-1, -1} // meet[side] — клетка встречи со стороны направления side
+	expanded := 0
+	for len(frontier[forward]) > 0 && len(frontier[backward]) > 0 && meet[forward] == -1 {
+		side := forward
+		if len(frontier[backward]) < len(frontier[forward]) {
+			side = backward
+		}
+		other := 1 - side
+
+		var next []int
+		for _, cell := range frontier[side] {
+			expanded++
+			x, y := cell/cols, cell%cols
+			for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+				nx, ny := x+dir[0], y+dir[1]
+				if !algorithms.IsValid(board, nx, ny) {
+					continue
+				}
+
+				neighbor := nx*cols + ny
+				if dist[other][neighbor] != -1 && dist[side][cell]+1+dist[other][neighbor] < best {
+					best = dist[side][cell] + 1 + dist[other][neighbor]
+					meet[side], meet[other] = cell, neighbor
+				}
+				if dist[side][neighbor] == -1 {
+					dist[side][neighbor] = dist[side][cell] + 1
+					parent[side][neighbor] = cell
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier[side] = next
+	}
+
+	if meet[forward] == -1 {
+		return algorithms.NotFound(expanded)
+	}
+
+	var path []algorithms.Node
+	for cell := meet[forward]; cell != -1; cell = parent[forward][cell] {
+		path = append(path, algorithms.Node{X: cell / cols, Y: cell % cols, G: dist[forward][cell]})
+	}
+	slices.Reverse(path)
+	for cell := meet[backward]; cell != -1; cell = parent[backward][cell] {
+		path = append(path, algorithms.Node{X: cell / cols, Y: cell % cols, G: best - dist[backward][cell]})
+	}
+
+	return algorithms.Result{Dist: best, Path: path, Expanded: expanded}
+}
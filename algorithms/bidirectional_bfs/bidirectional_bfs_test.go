@@ -0,0 +1,33 @@
+package bidirectional_bfs
+
+import (
+	"testing"
+
+	"algo/algorithms/dijkstra"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+// TestExpandsFewerNodesThanDijkstra проверяет, что встречный обход раскрывает меньше узлов, чем однонаправленный
+func TestExpandsFewerNodesThanDijkstra(t *testing.T) {
+	board, err := maze.GenerateRandom(200, 200, 0.1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start, targets := [2]int{100, 50}, [][2]int{{100, 150}}
+	board[100][50], board[100][150] = false, false
+
+	result := BidirectionalBFS(board, start[0], start[1], targets)
+	reference := dijkstra.Dijkstra(board, start[0], start[1], targets)
+	if result.Dist != reference.Dist {
+		t.Fatalf("dist %d, dijkstra %d", result.Dist, reference.Dist)
+	}
+	if result.Expanded*4 > reference.Expanded*3 {
+		t.Errorf("expanded %d nodes, dijkstra %d", result.Expanded, reference.Expanded)
+	}
+}
+
+func BenchmarkBidirectionalBFSGenerated401x401(b *testing.B) {
+	testboards.BenchmarkGenerated401x401(b, BidirectionalBFS)
+}
@@ -0,0 +1,249 @@
+package cbs
+
+import (
+	"container/heap"
+	"context"
+	"slices"
+)
+
+// Name имя в метриках
+const Name = "cbs"
+
+// DefaultMaxNodes наибольшее количество узлов дерева ограничений, которые раскрывает поиск по умолчанию.
+// Количество узлов растет экспоненциально с числом конфликтов, поэтому без ограничения плотный запрос
+// может искать решение неограниченно долго
+const DefaultMaxNodes = 10000
+
+// checkEvery количество раскрытых состояний между проверками отмены контекста
+const checkEvery = 1024
+
+// Agent задача одного агента: клетки старта и цели
+type Agent struct {
+	Start, Goal [2]int
+}
+
+// Solution результат поиска. Paths[i][t] — клетка i-го агента в момент t, после конца пути агент стоит в цели.
+// Если решение не найдено, Paths пуст
+type Solution struct {
+	Paths        [][][2]int
+	Makespan     int  // Момент, когда последний агент окончательно пришел в цель
+	SumOfCosts   int  // Сумма моментов прихода агентов в цель
+	Expanded     int  // Количество состояний, раскрытых поиском в пространстве-времени
+	Nodes        int  // Количество раскрытых узлов дерева ограничений
+	LimitReached bool // Поиск остановлен по ограничению на количество узлов или отменой контекста, решение может существовать
+}
+
+// constraint запрещает агенту находиться в клетке cell в момент t или, если from не -1, переходить
+// из from в cell между моментами t-1 и t
+type constraint struct {
+	agent int
+	from  int
+	cell  int
+	t     int
+}
+
+// node узел дерева ограничений. Ограничения хранятся цепочкой до корня, чтобы не копировать их в каждом узле
+type node struct {
+	constraint *constraint
+	parent     *node
+	paths      [][]int
+	cost       int
+	order      int
+}
+
+// grid лабиринт с нумерацией клеток x*cols+y
+type grid struct {
+	board [][]bool
+	cols  int
+	free  int
+}
+
+// Solve ищет пути агентов без столкновений алгоритмом Conflict-Based Search. Нижний уровень — A* в пространстве-времени
+// с ожиданием на месте, верхний — поиск по дереву ограничений в порядке суммы стоимостей. Конфликтом считается
+// нахождение двух агентов в одной клетке в один момент и обмен клетками за один ход. Найденное решение минимально
+// по сумме стоимостей. maxNodes ограничивает количество раскрытых узлов дерева, 0 — DefaultMaxNodes.
+// Даже один узел может потребовать долгого поиска в пространстве-времени, поэтому поиск останавливается и при отмене ctx
+func Solve(ctx context.Context, board [][]bool, agents []Agent, maxNodes int) Solution {
+	if maxNodes <= 0 {
+		maxNodes = DefaultMaxNodes
+	}
+
+	g := &grid{board: board, cols: len(board[0])}
+	for _, row := range board {
+		for _, wall := range row {
+			if !wall {
+				g.free++
+			}
+		}
+	}
+
+	planners := make([]*planner, len(agents))
+	for i, agent := range agents {
+		planners[i] = newPlanner(ctx, g, g.index(agent.Start), g.index(agent.Goal))
+	}
+
+	var solution Solution
+	root := &node{paths: make([][]int, len(agents))}
+	for i, p := range planners {
+		path, expanded, err := p.plan(nil, root.paths)
+		solution.Expanded += expanded
+		if err != nil {
+			solution.LimitReached = true
+			return solution
+		}
+		if path == nil {
+			return solution
+		}
+		root.paths[i] = path
+		root.cost += len(path) - 1
+	}
+
+	open := &nodeQueue{root}
+	created := 1
+	for open.Len() > 0 {
+		if solution.Nodes == maxNodes || ctx.Err() != nil {
+			solution.LimitReached = true
+			return solution
+		}
+		current := heap.Pop(open).(*node)
+		solution.Nodes++
+
+		first, found := findConflict(current.paths)
+		if !found {
+			return g.solution(current.paths, solution)
+		}
+
+		for _, c := range first.constraints() {
+			child := &node{constraint: &c, parent: current, paths: slices.Clone(current.paths), order: created}
+			created++
+
+			others := slices.Clone(child.paths)
+			others[c.agent] = nil
+			path, expanded, err := planners[c.agent].plan(child.constraintsFor(c.agent), others)
+			solution.Expanded += expanded
+			if err != nil {
+				solution.LimitReached = true
+				return solution
+			}
+			if path == nil {
+				continue
+			}
+			child.paths[c.agent] = path
+			child.cost = current.cost - (len(current.paths[c.agent]) - 1) + (len(path) - 1)
+			heap.Push(open, child)
+		}
+	}
+
+	return solution
+}
+
+func (g *grid) index(cell [2]int) int {
+	return cell[0]*g.cols + cell[1]
+}
+
+func (g *grid) solution(paths [][]int, solution Solution) Solution {
+	solution.Paths = make([][][2]int, len(paths))
+	for i, path := range paths {
+		solution.Paths[i] = make([][2]int, len(path))
+		for t, cell := range path {
+			solution.Paths[i][t] = [2]int{cell / g.cols, cell % g.cols}
+		}
+		cost := len(path) - 1
+		solution.SumOfCosts += cost
+		solution.Makespan = max(solution.Makespan, cost)
+	}
+	return solution
+}
+
+// constraintsFor собирает ограничения агента от узла до корня
+func (n *node) constraintsFor(agent int) []constraint {
+	var constraints []constraint
+	for current := n; current != nil; current = current.parent {
+		if current.constraint != nil && current.constraint.agent == agent {
+			constraints = append(constraints, *current.constraint)
+		}
+	}
+	return constraints
+}
+
+// conflict конфликт агентов a и b в момент t: в одной клетке или при обмене клетками между t-1 и t
+type conflict struct {
+	a, b   int
+	t      int
+	cellA  int // Клетка агента a в момент t
+	fromA  int // Клетка агента a в момент t-1 для обмена, -1 для конфликта в клетке
+	fromB  int
+	isSwap bool
+}
+
+// constraints возвращает ограничения двух потомков узла: каждый запрещает конфликт одному из агентов
+func (c conflict) constraints() []constraint {
+	if c.isSwap {
+		return []constraint{
+			{agent: c.a, from: c.fromA, cell: c.fromB, t: c.t},
+			{agent: c.b, from: c.fromB, cell: c.fromA, t: c.t},
+		}
+	}
+	return []constraint{
+		{agent: c.a, from: -1, cell: c.cellA, t: c.t},
+		{agent: c.b, from: -1, cell: c.cellA, t: c.t},
+	}
+}
+
+// at возвращает клетку агента в момент t, после конца пути агент стоит в цели
+func at(path []int, t int) int {
+	return path[min(t, len(path)-1)]
+}
+
+// findConflict находит самый ранний конфликт между путями
+func findConflict(paths [][]int) (conflict, bool) {
+	horizon := 0
+	for _, path := range paths {
+		horizon = max(horizon, len(path))
+	}
+
+	for t := 0; t < horizon; t++ {
+		for a := range paths {
+			for b := a + 1; b < len(paths); b++ {
+				cellA, cellB := at(paths[a], t), at(paths[b], t)
+				if cellA == cellB {
+					return conflict{a: a, b: b, t: t, cellA: cellA, fromA: -1, fromB: -1}, true
+				}
+				if t > 0 {
+					fromA, fromB := at(paths[a], t-1), at(paths[b], t-1)
+					if fromA == cellB && fromB == cellA {
+						return conflict{a: a, b: b, t: t, cellA: cellA, fromA: fromA, fromB: fromB, isSwap: true}, true
+					}
+				}
+			}
+		}
+	}
+
+	return conflict{}, false
+}
+
+// nodeQueue очередь узлов дерева ограничений по сумме стоимостей, при равенстве — в порядке создания
+type nodeQueue []*node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].order < q[j].order
+}
+
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeQueue) Push(x interface{}) {
+	*q = append(*q, x.(*node))
+}
+
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[0 : n-1]
+	return it
+}
@@ -0,0 +1,199 @@
+package cbs
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"algo/algorithms"
+	"algo/maze"
+)
+
+// checkSolution проверяет, что пути начинаются в стартах, заканчиваются в целях, состоят из ходов в соседнюю
+// клетку или ожиданий и не сталкиваются, а makespan и сумма стоимостей соответствуют путям
+func checkSolution(t *testing.T, board [][]bool, agents []Agent, solution Solution) {
+	t.Helper()
+
+	if len(solution.Paths) != len(agents) {
+		t.Fatalf("got %d paths for %d agents", len(solution.Paths), len(agents))
+	}
+
+	makespan, sum := 0, 0
+	for i, path := range solution.Paths {
+		if path[0] != agents[i].Start || path[len(path)-1] != agents[i].Goal {
+			t.Fatalf("agent %d goes from %v to %v", i, path[0], path[len(path)-1])
+		}
+		for step, cell := range path {
+			if !algorithms.IsValid(board, cell[0], cell[1]) {
+				t.Fatalf("agent %d: cell %v at %d is wall", i, cell, step)
+			}
+			if step > 0 && abs(cell[0]-path[step-1][0])+abs(cell[1]-path[step-1][1]) > 1 {
+				t.Fatalf("agent %d jumps from %v to %v at %d", i, path[step-1], cell, step)
+			}
+		}
+		makespan = max(makespan, len(path)-1)
+		sum += len(path) - 1
+	}
+	if solution.Makespan != makespan || solution.SumOfCosts != sum {
+		t.Fatalf("makespan %d, sum of costs %d, paths give %d and %d", solution.Makespan, solution.SumOfCosts, makespan, sum)
+	}
+
+	position := func(path [][2]int, step int) [2]int {
+		return path[min(step, len(path)-1)]
+	}
+	for step := 0; step <= makespan; step++ {
+		for a := range solution.Paths {
+			for b := a + 1; b < len(solution.Paths); b++ {
+				pa, pb := position(solution.Paths[a], step), position(solution.Paths[b], step)
+				if pa == pb {
+					t.Fatalf("agents %d and %d meet at %v at %d", a, b, pa, step)
+				}
+				if step > 0 && pa == position(solution.Paths[b], step-1) && pb == position(solution.Paths[a], step-1) {
+					t.Fatalf("agents %d and %d swap %v and %v at %d", a, b, pa, pb, step)
+				}
+			}
+		}
+	}
+}
+
+func parseBoard(rows ...string) [][]bool {
+	board := make([][]bool, len(rows))
+	for i, row := range rows {
+		board[i] = make([]bool, len(row))
+		for j, cell := range row {
+			board[i][j] = cell == '#'
+		}
+	}
+	return board
+}
+
+func TestSwapWithPocket(t *testing.T) {
+	// Агенты меняются местами в коридоре, одному из них нужно переждать в нише
+	board := parseBoard(
+		"#.#",
+		"...",
+	)
+	agents := []Agent{{Start: [2]int{1, 0}, Goal: [2]int{1, 2}}, {Start: [2]int{1, 2}, Goal: [2]int{1, 0}}}
+
+	solution := Solve(context.Background(), board, agents, 0)
+	checkSolution(t, board, agents, solution)
+	// Один агент заходит в нишу и выходит из нее за 4 хода, второй пропускает его, ожидая один ход: 4 + 3
+	if solution.SumOfCosts != 7 || solution.Makespan != 4 {
+		t.Errorf("sum of costs %d, makespan %d, want 7 and 4", solution.SumOfCosts, solution.Makespan)
+	}
+}
+
+func TestCorridorSwapIsUnsolvable(t *testing.T) {
+	board := parseBoard("....")
+	agents := []Agent{{Start: [2]int{0, 0}, Goal: [2]int{0, 3}}, {Start: [2]int{0, 3}, Goal: [2]int{0, 0}}}
+
+	solution := Solve(context.Background(), board, agents, 200)
+	if len(solution.Paths) != 0 || !solution.LimitReached {
+		t.Errorf("got %d paths, limit reached %t, want no paths and limit reached", len(solution.Paths), solution.LimitReached)
+	}
+}
+
+// TestDeadline проверяет, что невыполнимый обмен в длинном коридоре и в лабиринте останавливается по отмене контекста,
+// хотя до ограничения на количество узлов дерева поиск в пространстве-времени занял бы десятки секунд
+func TestDeadline(t *testing.T) {
+	labyrinth, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		board  [][]bool
+		agents []Agent
+	}{
+		{
+			name:   "corridor",
+			board:  parseBoard(strings.Repeat(".", 150)),
+			agents: []Agent{{Start: [2]int{0, 0}, Goal: [2]int{0, 149}}, {Start: [2]int{0, 149}, Goal: [2]int{0, 0}}},
+		},
+		{
+			name:   "labyrinth",
+			board:  labyrinth,
+			agents: []Agent{{Start: [2]int{1, 0}, Goal: [2]int{39, 40}}, {Start: [2]int{39, 40}, Goal: [2]int{1, 0}}},
+		},
+	}
+
+	for _, test := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		startTime := time.Now()
+		solution := Solve(ctx, test.board, test.agents, 0)
+		elapsed := time.Since(startTime)
+		cancel()
+
+		if len(solution.Paths) != 0 || !solution.LimitReached {
+			t.Errorf("%s: got %d paths, limit reached %t, want no paths and limit reached", test.name, len(solution.Paths), solution.LimitReached)
+		}
+		if elapsed > time.Second {
+			t.Errorf("%s: search took %s after a 100ms deadline", test.name, elapsed)
+		}
+	}
+}
+
+func TestUnreachableGoal(t *testing.T) {
+	board := parseBoard(".#.")
+	agents := []Agent{{Start: [2]int{0, 0}, Goal: [2]int{0, 2}}}
+
+	solution := Solve(context.Background(), board, agents, 0)
+	if len(solution.Paths) != 0 || solution.LimitReached {
+		t.Errorf("got %d paths, limit reached %t, want no paths without limit", len(solution.Paths), solution.LimitReached)
+	}
+}
+
+func TestRandomBoards(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		board, err := maze.GenerateRandom(6+rnd.Intn(5), 6+rnd.Intn(5), 0.2, rnd.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		used := make(map[[2]int]bool)
+		free := func() [2]int {
+			for {
+				cell := [2]int{rnd.Intn(len(board)), rnd.Intn(len(board[0]))}
+				if algorithms.IsValid(board, cell[0], cell[1]) && !used[cell] {
+					used[cell] = true
+					return cell
+				}
+			}
+		}
+		agents := make([]Agent, 2+rnd.Intn(3))
+		for j := range agents {
+			agents[j].Start = free()
+		}
+		for j := range agents {
+			agents[j].Goal = free()
+		}
+
+		solution := Solve(context.Background(), board, agents, 0)
+		if len(solution.Paths) == 0 {
+			continue
+		}
+		checkSolution(t, board, agents, solution)
+
+		// Сумма стоимостей не меньше суммы кратчайших путей без учета других агентов
+		g := &grid{board: board, cols: len(board[0])}
+		lower := 0
+		for _, agent := range agents {
+			lower += g.bfs(g.index(agent.Goal))[g.index(agent.Start)]
+		}
+		if solution.SumOfCosts < lower {
+			t.Fatalf("board %d: sum of costs %d is below independent lower bound %d", i, solution.SumOfCosts, lower)
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
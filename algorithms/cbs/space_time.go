@@ -0,0 +1,206 @@
+package cbs
+
+import (
+	"container/heap"
+	"context"
+	"slices"
+
+	"algo/algorithms"
+)
+
+// planner ищет путь одного агента A* в пространстве-времени. Эвристика — расстояние до цели без учета
+// других агентов, посчитанное обходом в ширину один раз на агента, поэтому она допустима и согласована
+type planner struct {
+	ctx   context.Context
+	g     *grid
+	start int
+	goal  int
+	h     []int // Расстояние от клетки до цели, -1 — цель недостижима
+}
+
+func newPlanner(ctx context.Context, g *grid, start, goal int) *planner {
+	return &planner{ctx: ctx, g: g, start: start, goal: goal, h: g.bfs(goal)}
+}
+
+// state состояние поиска: клетка в момент t
+type state struct {
+	cell      int
+	t         int
+	f         int
+	conflicts int // Количество столкновений с путями других агентов на пути до состояния
+	parent    int // Индекс родителя в списке раскрытых состояний, -1 у старта
+}
+
+// plan ищет кратчайший по времени путь от старта до цели с учетом ограничений. Путь заканчивается, когда агент
+// приходит в цель и может оставаться в ней: позже в цели нет запретов. Среди кратчайших путей выбирается путь
+// с наименьшим количеством столкновений с путями других агентов others, это уменьшает число конфликтов, которые
+// придется разрешать на верхнем уровне. Возвращает nil, если пути нет, и ошибку контекста, если поиск отменен
+func (p *planner) plan(constraints []constraint, others [][]int) ([]int, int, error) {
+	if p.h[p.start] == -1 {
+		return nil, 0, nil
+	}
+
+	type vertex struct{ cell, t int }
+	type edge struct{ from, cell, t int }
+	vertices, edges := make(map[vertex]bool), make(map[edge]bool)
+	lastGoal, latest := -1, 0
+	for _, c := range constraints {
+		if c.from == -1 {
+			vertices[vertex{c.cell, c.t}] = true
+			if c.cell == p.goal {
+				lastGoal = max(lastGoal, c.t)
+			}
+		} else {
+			edges[edge{c.from, c.cell, c.t}] = true
+		}
+		latest = max(latest, c.t)
+	}
+	if vertices[vertex{p.start, 0}] {
+		return nil, 0, nil
+	}
+
+	// После последнего ограничения до цели можно дойти, не проходя ни одну клетку дважды,
+	// поэтому более длинные пути рассматривать не нужно
+	limit := latest + p.g.free
+
+	avoid := newAvoidanceTable(others)
+
+	expanded := 0
+	var closed []state
+	visited := make(map[vertex]bool)
+	open := &stateQueue{{cell: p.start, f: p.h[p.start], parent: -1}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(state)
+		if visited[vertex{current.cell, current.t}] {
+			continue
+		}
+		visited[vertex{current.cell, current.t}] = true
+		closed = append(closed, current)
+		expanded++
+		if expanded%checkEvery == 0 {
+			if err := p.ctx.Err(); err != nil {
+				return nil, expanded, err
+			}
+		}
+
+		if current.cell == p.goal && current.t > lastGoal {
+			var path []int
+			for i := len(closed) - 1; i != -1; i = closed[i].parent {
+				path = append(path, closed[i].cell)
+			}
+			slices.Reverse(path)
+			return path, expanded, nil
+		}
+		if current.t >= limit {
+			continue
+		}
+
+		parent := len(closed) - 1
+		p.g.moves(current.cell, func(next int) {
+			t := current.t + 1
+			if visited[vertex{next, t}] || vertices[vertex{next, t}] || edges[edge{current.cell, next, t}] || p.h[next] == -1 {
+				return
+			}
+			conflicts := current.conflicts + avoid.count(next, t)
+			heap.Push(open, state{cell: next, t: t, f: t + p.h[next], conflicts: conflicts, parent: parent})
+		})
+	}
+
+	return nil, expanded, nil
+}
+
+// avoidanceTable таблица занятости клеток другими агентами: сколько агентов находится в клетке в момент t
+// и с какого момента агент стоит в своей цели
+type avoidanceTable struct {
+	occupied map[[2]int]int
+	arrived  map[int][]int
+}
+
+func newAvoidanceTable(paths [][]int) avoidanceTable {
+	table := avoidanceTable{occupied: make(map[[2]int]int), arrived: make(map[int][]int)}
+	for _, path := range paths {
+		if path == nil {
+			continue
+		}
+		for t, cell := range path[:len(path)-1] {
+			table.occupied[[2]int{cell, t}]++
+		}
+		goal := path[len(path)-1]
+		table.arrived[goal] = append(table.arrived[goal], len(path)-1)
+	}
+	return table
+}
+
+// count возвращает количество других агентов в клетке в момент t
+func (table avoidanceTable) count(cell, t int) int {
+	count := table.occupied[[2]int{cell, t}]
+	for _, arrival := range table.arrived[cell] {
+		if t >= arrival {
+			count++
+		}
+	}
+	return count
+}
+
+// moves вызывает visit для клетки и ее свободных соседей: агент может остаться на месте или сделать ход
+func (g *grid) moves(cell int, visit func(next int)) {
+	visit(cell)
+	x, y := cell/g.cols, cell%g.cols
+	for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+		if nx, ny := x+dir[0], y+dir[1]; algorithms.IsValid(g.board, nx, ny) {
+			visit(nx*g.cols + ny)
+		}
+	}
+}
+
+// bfs считает расстояния от клетки до всех клеток лабиринта, -1 — клетка недостижима
+func (g *grid) bfs(from int) []int {
+	dist := make([]int, len(g.board)*g.cols)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	dist[from] = 0
+	queue := []int{from}
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		g.moves(current, func(next int) {
+			if dist[next] == -1 {
+				dist[next] = dist[current] + 1
+				queue = append(queue, next)
+			}
+		})
+	}
+
+	return dist
+}
+
+// stateQueue очередь состояний по f, при равенстве — сначала с меньшим количеством столкновений,
+// затем более поздние, они ближе к цели
+type stateQueue []state
+
+func (q stateQueue) Len() int { return len(q) }
+
+func (q stateQueue) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	if q[i].conflicts != q[j].conflicts {
+		return q[i].conflicts < q[j].conflicts
+	}
+	return q[i].t > q[j].t
+}
+
+func (q stateQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *stateQueue) Push(x interface{}) {
+	*q = append(*q, x.(state))
+}
+
+func (q *stateQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[0 : n-1]
+	return it
+}
@@ -0,0 +1,98 @@
+package dijkstra
+
+import (
+	"container/heap"
+	"slices"
+
+	"algo/algorithms"
+)
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{ID: 3, Name: "dijkstra", Title: "Dijkstra", Solve: Dijkstra})
+}
+
+// PriorityQueue реализует очередь приоритетов для узлов
+type PriorityQueue []*algorithms.Node
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+
+func (pq PriorityQueue) Less(i, j int) bool {
+	return pq[i].G < pq[j].G
+}
+
+func (pq PriorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *PriorityQueue) Push(x interface{}) {
+	item := x.(*algorithms.Node)
+	*pq = append(*pq, item)
+}
+
+func (pq *PriorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*pq = old[0 : n-1]
+	return item
+}
+
+// reconstructPath восстанавливает путь от целевого узла до стартового
+func reconstructPath(current *algorithms.Node) []algorithms.Node {
+	path := make([]algorithms.Node, 0)
+	for current != nil {
+		path = append(path, *current)
+		current = current.Parent
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Dijkstra алгоритм поиска кратчайшего пути без эвристики, используется как эталон оптимальности
+func Dijkstra(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	isTarget := make(map[[2]int]bool, len(targets))
+	for _, target := range targets {
+		isTarget[target] = true
+	}
+
+	openList := &PriorityQueue{}
+	heap.Init(openList)
+	closedList := make(map[[2]int]bool)
+	bestG := make(map[[2]int]int)
+	startNode := &algorithms.Node{X: startX, Y: startY}
+	heap.Push(openList, startNode)
+	bestG[[2]int{startX, startY}] = 0
+
+	expanded := 0
+	for openList.Len() > 0 {
+		current := heap.Pop(openList).(*algorithms.Node)
+		key := [2]int{current.X, current.Y}
+		if closedList[key] {
+			continue
+		}
+		closedList[key] = true
+		expanded++
+
+		if isTarget[key] {
+			return algorithms.Result{Dist: current.G, Path: reconstructPath(current), Expanded: expanded}
+		}
+
+		neighbors := [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
+		for _, dir := range neighbors {
+			x, y := current.X+dir[0], current.Y+dir[1]
+			if !algorithms.IsValid(board, x, y) || closedList[[2]int{x, y}] {
+				continue
+			}
+
+			tentativeG := current.G + 1
+			if g, found := bestG[[2]int{x, y}]; found && g <= tentativeG {
+				continue
+			}
+			bestG[[2]int{x, y}] = tentativeG
+			heap.Push(openList, &algorithms.Node{X: x, Y: y, G: tentativeG, F: tentativeG, Parent: current})
+		}
+	}
+
+	return algorithms.NotFound(expanded)
+}
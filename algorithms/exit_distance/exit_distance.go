@@ -0,0 +1,77 @@
+package exit_distance
+
+import "algo/algorithms"
+
+// Unreachable расстояние для стен и клеток, из которых нельзя выйти на границу лабиринта
+const Unreachable = -1
+
+// Field поле расстояний до ближайшего выхода — свободной клетки на границе лабиринта.
+// Строится один раз для версии лабиринта, после чего путь до ближайшего выхода из любой клетки
+// восстанавливается спуском по полю за время, пропорциональное длине пути
+type Field struct {
+	Dist [][]int // Dist[x][y] — число шагов до ближайшего выхода или Unreachable
+	Max  int     // Наибольшее конечное расстояние
+}
+
+// Compute строит поле обходом в ширину одновременно из всех выходов
+func Compute(board [][]bool) *Field {
+	field := &Field{Dist: make([][]int, len(board))}
+	for x, row := range board {
+		field.Dist[x] = make([]int, len(row))
+		for y := range row {
+			field.Dist[x][y] = Unreachable
+		}
+	}
+
+	queue := algorithms.GetBoundaryCells(board, -1, -1)
+	for _, exit := range queue {
+		field.Dist[exit[0]][exit[1]] = 0
+	}
+
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		dist := field.Dist[current[0]][current[1]]
+		field.Max = max(field.Max, dist)
+
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			x, y := current[0]+dir[0], current[1]+dir[1]
+			if !algorithms.IsValid(board, x, y) || field.Dist[x][y] != Unreachable {
+				continue
+			}
+			field.Dist[x][y] = dist + 1
+			queue = append(queue, [2]int{x, y})
+		}
+	}
+
+	return field
+}
+
+// IsExit сообщает, является ли клетка выходом
+func (field *Field) IsExit(x, y int) bool {
+	return field.Dist[x][y] == 0
+}
+
+// Nearest возвращает кратчайший путь от клетки до ближайшего выхода, спускаясь по полю к соседу
+// с расстоянием на единицу меньше. Для выхода возвращается путь из одной клетки, хотя
+// GetBoundaryCells исключает стартовую клетку из целей, поэтому такой случай вызывающий решает сам
+func (field *Field) Nearest(x, y int) algorithms.Result {
+	dist := field.Dist[x][y]
+	if dist == Unreachable {
+		return algorithms.NotFound(0)
+	}
+
+	path := make([]algorithms.Node, 0, dist+1)
+	path = append(path, algorithms.Node{X: x, Y: y})
+	for g := 1; g <= dist; g++ {
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if nx >= 0 && nx < len(field.Dist) && ny >= 0 && ny < len(field.Dist[nx]) && field.Dist[nx][ny] == dist-g {
+				x, y = nx, ny
+				break
+			}
+		}
+		path = append(path, algorithms.Node{X: x, Y: y, G: g})
+	}
+
+	return algorithms.Result{Dist: dist, Path: path, Expanded: len(path)}
+}
@@ -0,0 +1,99 @@
+package exit_distance
+
+import (
+	"math/rand"
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/dijkstra"
+	"algo/maze"
+)
+
+func TestNearestMatchesDijkstra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := range 50 {
+		rows, cols := 3+rnd.Intn(20), 3+rnd.Intn(20)
+		board, err := maze.GenerateRandom(rows, cols, 0.35, int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		field := Compute(board)
+		for x, row := range board {
+			for y, wall := range row {
+				if wall {
+					if field.Dist[x][y] != Unreachable {
+						t.Fatalf("board %d: wall (%d,%d) has dist %d", i, x, y, field.Dist[x][y])
+					}
+					continue
+				}
+				if field.IsExit(x, y) {
+					continue
+				}
+
+				want := dijkstra.Dijkstra(board, x, y, algorithms.GetBoundaryCells(board, x, y))
+				got := field.Nearest(x, y)
+				if got.Dist != want.Dist {
+					t.Fatalf("board %d: dist from (%d,%d) = %d, dijkstra %d", i, x, y, got.Dist, want.Dist)
+				}
+				checkPath(t, board, field, got)
+			}
+		}
+	}
+}
+
+// checkPath проверяет, что путь идет по свободным соседним клеткам и заканчивается на выходе
+func checkPath(t *testing.T, board [][]bool, field *Field, result algorithms.Result) {
+	t.Helper()
+
+	if result.Dist == algorithms.PathNotFound {
+		if len(result.Path) != 0 {
+			t.Fatalf("unreachable cell has path of %d nodes", len(result.Path))
+		}
+		return
+	}
+
+	if len(result.Path) != result.Dist+1 {
+		t.Fatalf("path has %d nodes for dist %d", len(result.Path), result.Dist)
+	}
+	for i, node := range result.Path {
+		if !algorithms.IsValid(board, node.X, node.Y) {
+			t.Fatalf("path node (%d,%d) is a wall", node.X, node.Y)
+		}
+		if i > 0 {
+			prev := result.Path[i-1]
+			if abs(node.X-prev.X)+abs(node.Y-prev.Y) != 1 {
+				t.Fatalf("path is not contiguous between (%d,%d) and (%d,%d)", prev.X, prev.Y, node.X, node.Y)
+			}
+		}
+	}
+	if last := result.Path[len(result.Path)-1]; !field.IsExit(last.X, last.Y) {
+		t.Fatalf("path ends at (%d,%d), which is not an exit", last.X, last.Y)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestComputeShippedLabyrinth(t *testing.T) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := Compute(board)
+	if field.Max <= 0 {
+		t.Fatalf("max dist = %d, expected positive", field.Max)
+	}
+
+	result := field.Nearest(1, 1)
+	want := dijkstra.Dijkstra(board, 1, 1, algorithms.GetBoundaryCells(board, 1, 1))
+	if result.Dist != want.Dist {
+		t.Errorf("dist from (1,1) = %d, dijkstra %d", result.Dist, want.Dist)
+	}
+	checkPath(t, board, field, result)
+}
@@ -0,0 +1,48 @@
+package greedy_best_first
+
+import (
+	"algo/algorithms"
+	"algo/algorithms/a_star"
+	"algo/algorithms/heuristics"
+)
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{
+		ID:          8,
+		Name:        "greedy",
+		Title:       "Greedy Best-First",
+		Solve:       GreedyBestFirst,
+		Approximate: true,
+		Configure:   Configure,
+		Heuristics:  true,
+		Movements:   true,
+	})
+}
+
+// GreedyBestFirst раскрывает узел, ближайший к цели по Манхэттену, не учитывая пройденное расстояние.
+// Это предельный случай взвешенного A* с бесконечным весом: путь находится быстрее всего,
+// но его длина ничем не ограничена относительно кратчайшей
+func GreedyBestFirst(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	return search(board, startX, startY, targets, heuristics.Grid4, heuristics.Manhattan)
+}
+
+// Configure возвращает жадный поиск с выбранными моделью перемещения и эвристикой, вес эвристики на порядок
+// раскрытия не влияет
+func Configure(options algorithms.Options) algorithms.Solver {
+	movement := options.Movement
+	if len(movement.Moves) == 0 {
+		movement = heuristics.Grid4
+	}
+	h := options.Heuristic
+	if h == nil {
+		h = movement.Heuristic
+	}
+
+	return func(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+		return search(board, startX, startY, targets, movement, h)
+	}
+}
+
+func search(board [][]bool, startX, startY int, targets [][2]int, movement heuristics.Movement, h heuristics.Heuristic) algorithms.Result {
+	return a_star.BestFirst(board, startX, startY, targets, movement.Moves, h, func(_, h int) int { return h })
+}
@@ -0,0 +1,236 @@
+package heuristics
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Heuristic оценивает длину пути между двумя клетками снизу
+type Heuristic interface {
+	Name() string
+	Between(x1, y1, x2, y2 int) int
+}
+
+// metric эвристика, которая зависит только от смещения между клетками
+type metric struct {
+	name     string
+	distance func(dx, dy int) int // dx и dy неотрицательны
+}
+
+func (m metric) Name() string { return m.name }
+
+func (m metric) Between(x1, y1, x2, y2 int) int {
+	return m.distance(abs(x1-x2), abs(y1-y2))
+}
+
+// Эвристики, которые можно выбрать в запросе. Дробные расстояния округляются вниз,
+// что сохраняет допустимость при целых стоимостях ходов
+var (
+	Manhattan Heuristic = metric{name: "manhattan", distance: func(dx, dy int) int { return dx + dy }}
+	Euclidean Heuristic = metric{name: "euclidean", distance: func(dx, dy int) int { return int(math.Sqrt(float64(dx*dx + dy*dy))) }}
+	Octile    Heuristic = metric{name: "octile", distance: func(dx, dy int) int { return max(dx, dy) + int((math.Sqrt2-1)*float64(min(dx, dy))) }}
+	Chebyshev Heuristic = metric{name: "chebyshev", distance: func(dx, dy int) int { return max(dx, dy) }}
+	Zero      Heuristic = metric{name: "zero", distance: func(dx, dy int) int { return 0 }} // A* с нулевой эвристикой — алгоритм Дейкстры
+)
+
+var all = []Heuristic{Manhattan, Euclidean, Octile, Chebyshev, Zero}
+
+// All возвращает эвристики, которые можно выбрать по имени
+func All() []Heuristic {
+	return slices.Clone(all)
+}
+
+// Get возвращает эвристику по имени
+func Get(name string) (Heuristic, bool) {
+	for _, h := range all {
+		if h.Name() == name {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// ToNearest возвращает оценку до ближайшей из целей, минимум допустимых оценок тоже допустим
+func ToNearest(h Heuristic, x, y int, targets [][2]int) int {
+	best := math.MaxInt
+	for _, target := range targets {
+		best = min(best, h.Between(x, y, target[0], target[1]))
+	}
+	return best
+}
+
+// Move ход модели перемещения
+type Move struct {
+	DX, DY int
+	Cost   float64
+}
+
+// Movement модель перемещения по сетке
+type Movement struct {
+	Name      string
+	Moves     []Move
+	Heuristic Heuristic // Самая точная из эвристик пакета, которая проходит Check для модели, используется по умолчанию
+}
+
+// Модели перемещения, которые можно выбрать в запросе. Стоимости ходов целые, поэтому длина пути остается целой.
+// Grid4 — ход в соседнюю по стороне клетку, его используют все алгоритмы сервиса по умолчанию. Алгоритмы
+// с произвольными углами считают длину отрезка по Манхэттену, поэтому для эвристик эта модель подходит и им.
+// Grid8 — ход в любую из восьми соседних клеток за единицу, как король в шахматах: для нее допустимы
+// только chebyshev и zero
+var (
+	Grid4 = Movement{
+		Name:      "grid4",
+		Moves:     []Move{{DX: 0, DY: 1, Cost: 1}, {DX: 0, DY: -1, Cost: 1}, {DX: 1, DY: 0, Cost: 1}, {DX: -1, DY: 0, Cost: 1}},
+		Heuristic: Manhattan,
+	}
+	Grid8 = Movement{
+		Name: "grid8",
+		Moves: []Move{
+			{DX: 0, DY: 1, Cost: 1}, {DX: 0, DY: -1, Cost: 1}, {DX: 1, DY: 0, Cost: 1}, {DX: -1, DY: 0, Cost: 1},
+			{DX: 1, DY: 1, Cost: 1}, {DX: 1, DY: -1, Cost: 1}, {DX: -1, DY: 1, Cost: 1}, {DX: -1, DY: -1, Cost: 1},
+		},
+		Heuristic: Chebyshev,
+	}
+)
+
+var movements = []Movement{Grid4, Grid8}
+
+// GetMovement возвращает модель перемещения по имени
+func GetMovement(name string) (Movement, bool) {
+	for _, movement := range movements {
+		if movement.Name == name {
+			return movement, true
+		}
+	}
+	return Movement{}, false
+}
+
+// checkRadius половина стороны квадрата смещений, на которых проверяется эвристика
+const checkRadius = 16
+
+// epsilon допуск при сравнении дробных стоимостей
+const epsilon = 1e-9
+
+// Check проверяет, что эвристика допустима и согласована для модели перемещения. Допустимость: оценка не больше
+// кратчайшего расстояния на сетке без стен, а стены расстояния только увеличивают. Согласованность: оценка
+// убывает за один ход не больше его стоимости, без этого A* с закрытым списком может вернуть не кратчайший путь.
+// Проверяются смещения не больше checkRadius, поэтому эвристика должна зависеть только от смещения между клетками
+// и расти с ним не быстрее линейно, как все эвристики пакета. Результат запоминается по именам эвристики и модели,
+// поэтому разные модели должны называться по-разному
+func Check(h Heuristic, movement Movement) error {
+	key := [2]string{h.Name(), movement.Name}
+	if err, found := checked.Load(key); found {
+		return err.(checkResult).err
+	}
+
+	err := check(h, movement)
+	checked.Store(key, checkResult{err: err})
+	return err
+}
+
+// checked результаты Check по именам эвристики и модели перемещения
+var checked sync.Map
+
+type checkResult struct {
+	err error
+}
+
+func check(h Heuristic, movement Movement) error {
+	if len(movement.Moves) == 0 {
+		return fmt.Errorf("movement %q has no moves", movement.Name)
+	}
+
+	dist := openGridDistances(movement)
+	size := 2*checkRadius + 1
+	for x := -checkRadius; x <= checkRadius; x++ {
+		for y := -checkRadius; y <= checkRadius; y++ {
+			estimate := float64(h.Between(x, y, 0, 0))
+			if d := dist[(x+checkRadius)*size+y+checkRadius]; estimate > d+epsilon {
+				return fmt.Errorf("heuristic %q is not admissible for %q movement: estimate %g for offset (%d, %d) exceeds distance %g",
+					h.Name(), movement.Name, estimate, x, y, d)
+			}
+
+			for _, move := range movement.Moves {
+				nx, ny := x+move.DX, y+move.DY
+				if abs(nx) > checkRadius || abs(ny) > checkRadius {
+					continue
+				}
+				if next := float64(h.Between(nx, ny, 0, 0)); estimate > move.Cost+next+epsilon {
+					return fmt.Errorf("heuristic %q is not consistent for %q movement: estimate drops from %g to %g on move (%d, %d) with cost %g",
+						h.Name(), movement.Name, estimate, next, move.DX, move.DY, move.Cost)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// openGridDistances считает алгоритмом Дейкстры расстояния от центра квадрата смещений до всех его клеток
+func openGridDistances(movement Movement) []float64 {
+	size := 2*checkRadius + 1
+	dist := make([]float64, size*size)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	center := checkRadius*size + checkRadius
+	dist[center] = 0
+	queue := &distanceQueue{{cell: center}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(distanceItem)
+		if current.dist > dist[current.cell] {
+			continue
+		}
+
+		x, y := current.cell/size, current.cell%size
+		for _, move := range movement.Moves {
+			nx, ny := x+move.DX, y+move.DY
+			if nx < 0 || nx >= size || ny < 0 || ny >= size {
+				continue
+			}
+			if next := nx*size + ny; current.dist+move.Cost < dist[next] {
+				dist[next] = current.dist + move.Cost
+				heap.Push(queue, distanceItem{cell: next, dist: dist[next]})
+			}
+		}
+	}
+
+	return dist
+}
+
+type distanceItem struct {
+	cell int
+	dist float64
+}
+
+// distanceQueue очередь приоритетов по расстоянию
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int { return len(q) }
+
+func (q distanceQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+
+func (q distanceQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *distanceQueue) Push(x interface{}) {
+	*q = append(*q, x.(distanceItem))
+}
+
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[0 : n-1]
+	return it
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
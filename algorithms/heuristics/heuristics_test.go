@@ -0,0 +1,90 @@
+package heuristics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+// grid8 перемещение в любую из восьми соседних клеток, diagonal — стоимость хода по диагонали
+func grid8(diagonal float64) Movement {
+	movement := Movement{Name: fmt.Sprintf("grid8-%g", diagonal), Moves: slices.Clone(Grid4.Moves)}
+	for _, dx := range []int{-1, 1} {
+		for _, dy := range []int{-1, 1} {
+			movement.Moves = append(movement.Moves, Move{DX: dx, DY: dy, Cost: diagonal})
+		}
+	}
+	return movement
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		movement Movement
+		valid    map[string]bool
+	}{
+		{
+			movement: Grid4,
+			valid:    map[string]bool{"manhattan": true, "euclidean": true, "octile": true, "chebyshev": true, "zero": true},
+		},
+		// Округленные вниз евклидова и октильная оценки допустимы, но при дробной стоимости диагонали
+		// теряют согласованность
+		{
+			movement: grid8(math.Sqrt2),
+			valid:    map[string]bool{"manhattan": false, "euclidean": false, "octile": false, "chebyshev": true, "zero": true},
+		},
+		{
+			movement: grid8(1),
+			valid:    map[string]bool{"manhattan": false, "euclidean": false, "octile": false, "chebyshev": true, "zero": true},
+		},
+		{
+			movement: Grid8,
+			valid:    map[string]bool{"manhattan": false, "euclidean": false, "octile": false, "chebyshev": true, "zero": true},
+		},
+	}
+
+	for _, test := range tests {
+		for _, h := range All() {
+			err := Check(h, test.movement)
+			if valid, found := test.valid[h.Name()]; !found || valid != (err == nil) {
+				t.Errorf("Check(%s, %s) = %v, want valid %t", h.Name(), test.movement.Name, err, valid)
+			}
+		}
+	}
+}
+
+func TestMovements(t *testing.T) {
+	for _, name := range []string{"grid4", "grid8"} {
+		movement, found := GetMovement(name)
+		if !found {
+			t.Fatalf("movement %q not found", name)
+		}
+		if err := Check(movement.Heuristic, movement); err != nil {
+			t.Errorf("default heuristic of %q: %v", name, err)
+		}
+	}
+	if _, found := GetMovement("hex"); found {
+		t.Error("unknown movement must not be found")
+	}
+}
+
+func TestGet(t *testing.T) {
+	for _, h := range All() {
+		if got, found := Get(h.Name()); !found || got.Name() != h.Name() {
+			t.Errorf("Get(%q) = %v, %t", h.Name(), got, found)
+		}
+	}
+	if _, found := Get("landmarks"); found {
+		t.Error("unknown heuristic must not be found")
+	}
+}
+
+func TestToNearest(t *testing.T) {
+	targets := [][2]int{{10, 0}, {3, 4}, {0, 9}}
+	if got := ToNearest(Manhattan, 0, 0, targets); got != 7 {
+		t.Errorf("manhattan to nearest = %d, want 7", got)
+	}
+	if got := ToNearest(Euclidean, 0, 0, targets); got != 5 {
+		t.Errorf("euclidean to nearest = %d, want 5", got)
+	}
+}
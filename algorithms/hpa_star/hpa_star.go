@@ -0,0 +1,533 @@
+package hpa_star
+
+import (
+	"container/heap"
+	"maps"
+	"slices"
+
+	"algo/algorithms"
+	"algo/algorithms/heuristics"
+)
+
+func init() {
+	algorithms.Register(algorithms.Algorithm{
+		ID:          6,
+		Name:        "hpa-star",
+		Title:       "HPA*",
+		Solve:       HPAStar,
+		Approximate: true,
+		Preprocess: func(board [][]bool) algorithms.Index {
+			return Build(board, DefaultClusterSize)
+		},
+	})
+}
+
+// DefaultClusterSize сторона кластера в клетках
+const DefaultClusterSize = 10
+
+// minWideEntrance длина входа, начиная с которой на нем ставятся два перехода по краям вместо одного посередине
+const minWideEntrance = 6
+
+// Ключи статистики в Result.Stats
+const (
+	StatClusters          = "clusters"
+	StatAbstractNodes     = "abstract_nodes"      // Количество узлов абстрактного графа
+	StatAbstractPathNodes = "abstract_path_nodes" // Количество узлов в абстрактном пути, включая старт и цель
+	StatAbstractExpanded  = "abstract_expanded"   // Количество узлов, раскрытых поиском по абстрактному графу
+	StatRefinedPathNodes  = "refined_path_nodes"  // Количество клеток в уточненном пути
+	StatRefineExpanded    = "refine_expanded"     // Количество клеток, раскрытых при уточнении пути внутри кластеров
+)
+
+// edge ребро абстрактного графа внутри кластера, cost — длина кратчайшего пути внутри кластера
+type edge struct {
+	to, cost int
+}
+
+// transition переход между соседними кластерами: пара соседних свободных клеток по разные стороны границы.
+// Клетка a лежит в кластере с меньшим номером
+type transition struct {
+	a, b int
+}
+
+// cluster узлы и ребра абстрактного графа в квадратной части лабиринта. После построения кластер не меняется,
+// поэтому неизмененные кластеры разделяются между версиями графа
+type cluster struct {
+	nodes []int          // Клетки узлов абстрактного графа, лежащие в кластере
+	edges map[int][]edge // Ребра между узлами кластера
+}
+
+// Graph абстрактный граф HPA*: лабиринт разбит на кластеры, узлы графа — клетки переходов между кластерами,
+// ребра — переходы и кратчайшие пути между узлами внутри одного кластера
+type Graph struct {
+	board        [][]bool
+	size         int
+	clusterRows  int
+	clusterCols  int
+	clusters     []*cluster
+	borders      map[[2]int][]transition // Переходы между парой соседних кластеров, ключ упорядочен по возрастанию
+	rebuilt      int
+	abstractSize int
+}
+
+// HPAStar строит абстрактный граф и ищет по нему путь. Для повторных запросов к одному лабиринту
+// выгоднее построить граф один раз через Build
+func HPAStar(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+	return Build(board, DefaultClusterSize).Solve(startX, startY, targets)
+}
+
+// Build разбивает лабиринт на кластеры со стороной size и строит абстрактный граф
+func Build(board [][]bool, size int) *Graph {
+	graph := &Graph{
+		board:       cloneBoard(board),
+		size:        size,
+		clusterRows: (len(board) + size - 1) / size,
+		clusterCols: (len(board[0]) + size - 1) / size,
+		borders:     make(map[[2]int][]transition),
+	}
+
+	graph.clusters = make([]*cluster, graph.clusterRows*graph.clusterCols)
+	for id := range graph.clusters {
+		for _, neighbor := range graph.neighborClusters(id) {
+			if id < neighbor {
+				graph.borders[[2]int{id, neighbor}] = graph.computeBorder(id, neighbor)
+			}
+		}
+	}
+	for id := range graph.clusters {
+		graph.clusters[id] = graph.buildCluster(id)
+	}
+	graph.rebuilt = len(graph.clusters)
+	graph.countNodes()
+
+	return graph
+}
+
+// Update возвращает граф для измененного лабиринта. Перестраиваются кластеры с измененными клетками
+// и соседние кластеры, если изменились переходы на общей границе. Остальные кластеры берутся из исходного графа
+func (graph *Graph) Update(board [][]bool) algorithms.Index {
+	if len(board) != len(graph.board) || len(board[0]) != len(graph.board[0]) {
+		return Build(board, graph.size)
+	}
+
+	changed := make(map[int]bool)
+	for x, row := range board {
+		for y, wall := range row {
+			if wall != graph.board[x][y] {
+				changed[graph.clusterOf(x, y)] = true
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return graph
+	}
+
+	updated := &Graph{
+		board:       cloneBoard(board),
+		size:        graph.size,
+		clusterRows: graph.clusterRows,
+		clusterCols: graph.clusterCols,
+		clusters:    slices.Clone(graph.clusters),
+		borders:     maps.Clone(graph.borders),
+	}
+
+	rebuild := maps.Clone(changed)
+	for id := range changed {
+		for _, neighbor := range updated.neighborClusters(id) {
+			key := [2]int{min(id, neighbor), max(id, neighbor)}
+			transitions := updated.computeBorder(key[0], key[1])
+			if !slices.Equal(transitions, updated.borders[key]) {
+				updated.borders[key] = transitions
+				rebuild[neighbor] = true
+			}
+		}
+	}
+
+	for id := range rebuild {
+		updated.clusters[id] = updated.buildCluster(id)
+	}
+	updated.rebuilt = len(rebuild)
+	updated.countNodes()
+
+	return updated
+}
+
+// Rebuilt возвращает количество кластеров, перестроенных при создании графа
+func (graph *Graph) Rebuilt() int {
+	return graph.rebuilt
+}
+
+// Solve ищет путь по абстрактному графу и уточняет его внутри кластеров.
+// Путь может быть длиннее кратчайшего, потому что переходы между кластерами выбираются заранее
+func (graph *Graph) Solve(startX, startY int, targets [][2]int) algorithms.Result {
+	board := graph.board
+	if len(targets) == 0 || !algorithms.IsValid(board, startX, startY) {
+		return algorithms.NotFound(0)
+	}
+
+	cols := len(board[0])
+	start := startX*cols + startY
+
+	isTarget := make(map[int]bool, len(targets))
+	targetsByCluster := make(map[int][]int)
+	for _, target := range targets {
+		if !algorithms.IsValid(board, target[0], target[1]) {
+			continue
+		}
+		cell := target[0]*cols + target[1]
+		if !isTarget[cell] {
+			isTarget[cell] = true
+			id := graph.clusterOf(target[0], target[1])
+			targetsByCluster[id] = append(targetsByCluster[id], cell)
+		}
+	}
+	if isTarget[start] {
+		return algorithms.Result{Dist: 0, Path: []algorithms.Node{{X: startX, Y: startY}}, Stats: graph.stats(1, 0, 1, 0)}
+	}
+
+	// Временные ребра связывают старт с узлами своего кластера, а узлы кластеров с целями — с целями
+	expanded := 0
+	extra := make(map[int][]edge)
+
+	startCluster := graph.clusterOf(startX, startY)
+	dist, _, visited := graph.clusterBFS(startCluster, start)
+	expanded += visited
+	for _, cell := range append(slices.Clone(graph.clusters[startCluster].nodes), targetsByCluster[startCluster]...) {
+		if d := dist[graph.local(startCluster, cell)]; d > 0 {
+			extra[start] = append(extra[start], edge{to: cell, cost: d})
+		}
+	}
+	for id, cells := range targetsByCluster {
+		for _, node := range graph.clusters[id].nodes {
+			dist, _, visited := graph.clusterBFS(id, node)
+			expanded += visited
+			for _, cell := range cells {
+				if d := dist[graph.local(id, cell)]; d >= 0 {
+					extra[node] = append(extra[node], edge{to: cell, cost: d})
+				}
+			}
+		}
+	}
+
+	abstractPath, abstractDist, abstractExpanded := graph.search(start, targets, isTarget, extra)
+	expanded += abstractExpanded
+	if abstractPath == nil {
+		return algorithms.NotFound(expanded)
+	}
+
+	path, refineExpanded := graph.refine(abstractPath)
+	expanded += refineExpanded
+
+	return algorithms.Result{
+		Dist:     abstractDist,
+		Path:     path,
+		Expanded: expanded,
+		Stats:    graph.stats(len(abstractPath), abstractExpanded, len(path), refineExpanded),
+	}
+}
+
+func (graph *Graph) stats(abstractPathNodes, abstractExpanded, refinedPathNodes, refineExpanded int) map[string]int {
+	return map[string]int{
+		StatClusters:          len(graph.clusters),
+		StatAbstractNodes:     graph.abstractSize,
+		StatAbstractPathNodes: abstractPathNodes,
+		StatAbstractExpanded:  abstractExpanded,
+		StatRefinedPathNodes:  refinedPathNodes,
+		StatRefineExpanded:    refineExpanded,
+	}
+}
+
+// item элемент очереди абстрактного поиска. Устаревшие элементы пропускаются при извлечении
+type item struct {
+	cell, g, f int
+}
+
+// PriorityQueue реализует очередь приоритетов по f
+type PriorityQueue []item
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+
+func (pq PriorityQueue) Less(i, j int) bool {
+	return pq[i].f < pq[j].f
+}
+
+func (pq PriorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *PriorityQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(item))
+}
+
+func (pq *PriorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	it := old[n-1]
+	*pq = old[0 : n-1]
+	return it
+}
+
+// search ищет A* по абстрактному графу от старта до ближайшей цели и возвращает клетки абстрактного пути
+func (graph *Graph) search(start int, targets [][2]int, isTarget map[int]bool, extra map[int][]edge) ([]int, int, int) {
+	cols := len(graph.board[0])
+	heuristic := func(cell int) int {
+		return heuristics.ToNearest(heuristics.Manhattan, cell/cols, cell%cols, targets)
+	}
+
+	g := map[int]int{start: 0}
+	parent := make(map[int]int)
+	closed := make(map[int]bool)
+	open := &PriorityQueue{{cell: start, g: 0, f: heuristic(start)}}
+
+	expanded := 0
+	for open.Len() > 0 {
+		current := heap.Pop(open).(item)
+		if closed[current.cell] || current.g != g[current.cell] {
+			continue
+		}
+		closed[current.cell] = true
+		expanded++
+
+		if isTarget[current.cell] {
+			path := []int{current.cell}
+			for cell := current.cell; cell != start; {
+				cell = parent[cell]
+				path = append(path, cell)
+			}
+			slices.Reverse(path)
+			return path, current.g, expanded
+		}
+
+		for _, next := range graph.edgesFrom(current.cell, extra) {
+			tentativeG := current.g + next.cost
+			if known, found := g[next.to]; found && known <= tentativeG {
+				continue
+			}
+			g[next.to] = tentativeG
+			parent[next.to] = current.cell
+			heap.Push(open, item{cell: next.to, g: tentativeG, f: tentativeG + heuristic(next.to)})
+		}
+	}
+
+	return nil, algorithms.PathNotFound, expanded
+}
+
+// edgesFrom возвращает ребра абстрактного графа из клетки: внутри кластера, переходы в соседние кластеры и временные ребра
+func (graph *Graph) edgesFrom(cell int, extra map[int][]edge) []edge {
+	cols := len(graph.board[0])
+	id := graph.clusterOf(cell/cols, cell%cols)
+
+	edges := slices.Clone(graph.clusters[id].edges[cell])
+	for _, neighbor := range graph.neighborClusters(id) {
+		for _, t := range graph.borders[[2]int{min(id, neighbor), max(id, neighbor)}] {
+			if t.a == cell {
+				edges = append(edges, edge{to: t.b, cost: 1})
+			} else if t.b == cell {
+				edges = append(edges, edge{to: t.a, cost: 1})
+			}
+		}
+	}
+
+	return append(edges, extra[cell]...)
+}
+
+// refine восстанавливает путь по клеткам: соседние узлы абстрактного пути соединяются напрямую,
+// остальные — кратчайшим путем внутри их общего кластера
+func (graph *Graph) refine(abstractPath []int) ([]algorithms.Node, int) {
+	cols := len(graph.board[0])
+	path := []algorithms.Node{{X: abstractPath[0] / cols, Y: abstractPath[0] % cols}}
+
+	expanded := 0
+	for i := 1; i < len(abstractPath); i++ {
+		from, to := abstractPath[i-1], abstractPath[i]
+		if abs(from/cols-to/cols)+abs(from%cols-to%cols) == 1 {
+			path = append(path, algorithms.Node{X: to / cols, Y: to % cols, G: len(path)})
+			continue
+		}
+
+		id := graph.clusterOf(from/cols, from%cols)
+		_, parent, visited := graph.clusterBFS(id, from)
+		expanded += visited
+
+		var segment []int
+		for cell := to; cell != from; cell = parent[graph.local(id, cell)] {
+			segment = append(segment, cell)
+		}
+		slices.Reverse(segment)
+		for _, cell := range segment {
+			path = append(path, algorithms.Node{X: cell / cols, Y: cell % cols, G: len(path)})
+		}
+	}
+
+	return path, expanded
+}
+
+// clusterOf возвращает номер кластера клетки
+func (graph *Graph) clusterOf(x, y int) int {
+	return (x/graph.size)*graph.clusterCols + y/graph.size
+}
+
+// neighborClusters возвращает номера кластеров, граничащих с кластером по стороне
+func (graph *Graph) neighborClusters(id int) []int {
+	row, col := id/graph.clusterCols, id%graph.clusterCols
+
+	var neighbors []int
+	if row > 0 {
+		neighbors = append(neighbors, id-graph.clusterCols)
+	}
+	if row < graph.clusterRows-1 {
+		neighbors = append(neighbors, id+graph.clusterCols)
+	}
+	if col > 0 {
+		neighbors = append(neighbors, id-1)
+	}
+	if col < graph.clusterCols-1 {
+		neighbors = append(neighbors, id+1)
+	}
+	return neighbors
+}
+
+// bounds возвращает границы кластера [x0, x1) x [y0, y1)
+func (graph *Graph) bounds(id int) (int, int, int, int) {
+	x0, y0 := id/graph.clusterCols*graph.size, id%graph.clusterCols*graph.size
+	return x0, y0, min(x0+graph.size, len(graph.board)), min(y0+graph.size, len(graph.board[0]))
+}
+
+// computeBorder находит входы на границе кластеров a < b — непрерывные участки, где свободны клетки
+// по обе стороны границы. На коротком входе ставится один переход посередине, на длинном — два по краям
+func (graph *Graph) computeBorder(a, b int) []transition {
+	cols := len(graph.board[0])
+	ax0, ay0, ax1, ay1 := graph.bounds(a)
+
+	// Пары клеток вдоль границы: для соседей по горизонтали граница вертикальная, и наоборот
+	var pairs [][2][2]int
+	if a/graph.clusterCols == b/graph.clusterCols {
+		for x := ax0; x < ax1; x++ {
+			pairs = append(pairs, [2][2]int{{x, ay1 - 1}, {x, ay1}})
+		}
+	} else {
+		for y := ay0; y < ay1; y++ {
+			pairs = append(pairs, [2][2]int{{ax1 - 1, y}, {ax1, y}})
+		}
+	}
+
+	var transitions []transition
+	add := func(pair [2][2]int) {
+		transitions = append(transitions, transition{a: pair[0][0]*cols + pair[0][1], b: pair[1][0]*cols + pair[1][1]})
+	}
+
+	for i := 0; i < len(pairs); {
+		open := func(pair [2][2]int) bool {
+			return !graph.board[pair[0][0]][pair[0][1]] && !graph.board[pair[1][0]][pair[1][1]]
+		}
+		if !open(pairs[i]) {
+			i++
+			continue
+		}
+
+		j := i
+		for j+1 < len(pairs) && open(pairs[j+1]) {
+			j++
+		}
+		if j-i+1 < minWideEntrance {
+			add(pairs[(i+j)/2])
+		} else {
+			add(pairs[i])
+			add(pairs[j])
+		}
+		i = j + 1
+	}
+
+	return transitions
+}
+
+// buildCluster собирает узлы кластера из переходов на его границах и считает расстояния между ними внутри кластера
+func (graph *Graph) buildCluster(id int) *cluster {
+	c := &cluster{edges: make(map[int][]edge)}
+
+	for _, neighbor := range graph.neighborClusters(id) {
+		for _, t := range graph.borders[[2]int{min(id, neighbor), max(id, neighbor)}] {
+			if id < neighbor {
+				c.nodes = append(c.nodes, t.a)
+			} else {
+				c.nodes = append(c.nodes, t.b)
+			}
+		}
+	}
+	slices.Sort(c.nodes)
+	c.nodes = slices.Compact(c.nodes)
+
+	for _, node := range c.nodes {
+		dist, _, _ := graph.clusterBFS(id, node)
+		for _, other := range c.nodes {
+			if d := dist[graph.local(id, other)]; other != node && d > 0 {
+				c.edges[node] = append(c.edges[node], edge{to: other, cost: d})
+			}
+		}
+	}
+
+	return c
+}
+
+// local возвращает индекс клетки внутри кластера
+func (graph *Graph) local(id, cell int) int {
+	cols := len(graph.board[0])
+	x0, y0, _, y1 := graph.bounds(id)
+	return (cell/cols-x0)*(y1-y0) + cell%cols - y0
+}
+
+// clusterBFS обходит в ширину клетки кластера от from. Возвращает расстояния (-1 для недостижимых клеток)
+// и предыдущие клетки пути по локальным индексам, а также количество посещенных клеток
+func (graph *Graph) clusterBFS(id, from int) ([]int, []int, int) {
+	cols := len(graph.board[0])
+	x0, y0, x1, y1 := graph.bounds(id)
+	width := y1 - y0
+
+	dist := make([]int, (x1-x0)*width)
+	parent := make([]int, len(dist))
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	dist[graph.local(id, from)] = 0
+	queue := []int{from}
+	for head := 0; head < len(queue); head++ {
+		cell := queue[head]
+		x, y := cell/cols, cell%cols
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if nx < x0 || nx >= x1 || ny < y0 || ny >= y1 || graph.board[nx][ny] {
+				continue
+			}
+			next := nx*cols + ny
+			if local := graph.local(id, next); dist[local] == -1 {
+				dist[local] = dist[graph.local(id, cell)] + 1
+				parent[local] = cell
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return dist, parent, len(queue)
+}
+
+func (graph *Graph) countNodes() {
+	graph.abstractSize = 0
+	for _, c := range graph.clusters {
+		graph.abstractSize += len(c.nodes)
+	}
+}
+
+func cloneBoard(board [][]bool) [][]bool {
+	clone := make([][]bool, len(board))
+	for i, row := range board {
+		clone[i] = slices.Clone(row)
+	}
+	return clone
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
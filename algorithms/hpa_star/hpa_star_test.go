@@ -0,0 +1,95 @@
+package hpa_star
+
+import (
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/dijkstra"
+	"algo/maze"
+)
+
+func TestUpdateRebuildsOnlyAffectedClusters(t *testing.T) {
+	board, err := maze.GenerateRandom(100, 100, 0.2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := Build(board, DefaultClusterSize)
+	if graph.Rebuilt() != 100 {
+		t.Fatalf("Build rebuilt %d clusters, want 100", graph.Rebuilt())
+	}
+
+	// Клетка внутри кластера не лежит на границе, поэтому переходы не меняются
+	board[55][55] = !board[55][55]
+	updated := graph.Update(board).(*Graph)
+	if updated.Rebuilt() != 1 {
+		t.Errorf("interior change rebuilt %d clusters, want 1", updated.Rebuilt())
+	}
+
+	// Закрываем всю границу между двумя кластерами: переходы исчезают у обоих
+	for x := 20; x < 30; x++ {
+		board[x][39] = true
+	}
+	updated = updated.Update(board).(*Graph)
+	if updated.Rebuilt() < 2 || updated.Rebuilt() > 5 {
+		t.Errorf("border change rebuilt %d clusters, want from 2 to 5", updated.Rebuilt())
+	}
+	if len(updated.borders[[2]int{23, 24}]) != 0 {
+		t.Errorf("closed border still has transitions %v", updated.borders[[2]int{23, 24}])
+	}
+
+	if unchanged := updated.Update(board); unchanged != updated {
+		t.Error("update without changes must return the same graph")
+	}
+}
+
+func TestSolveStats(t *testing.T) {
+	board, err := maze.Generate(101, 101, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := [][2]int{{99, 100}}
+	result := Build(board, DefaultClusterSize).Solve(1, 0, targets)
+	optimal := dijkstra.Dijkstra(board, 1, 0, targets)
+	if result.Dist == algorithms.PathNotFound || result.Dist < optimal.Dist {
+		t.Fatalf("dist=%d, optimal %d", result.Dist, optimal.Dist)
+	}
+
+	if result.Stats[StatClusters] != 121 {
+		t.Errorf("clusters = %d, want 121", result.Stats[StatClusters])
+	}
+	if result.Stats[StatRefinedPathNodes] != len(result.Path) || result.Stats[StatRefinedPathNodes] != result.Dist+1 {
+		t.Errorf("refined path has %d nodes, dist %d, stats %v", len(result.Path), result.Dist, result.Stats)
+	}
+	if nodes := result.Stats[StatAbstractPathNodes]; nodes < 2 || nodes >= len(result.Path) {
+		t.Errorf("abstract path has %d nodes, refined %d", nodes, len(result.Path))
+	}
+}
+
+func BenchmarkHPAStarRandom1000x1000(b *testing.B) {
+	board, err := maze.GenerateRandom(1000, 1000, 0.25, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	graph := Build(board, DefaultClusterSize)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	var result algorithms.Result
+	for i := 0; i < b.N; i++ {
+		result = graph.Solve(0, 0, [][2]int{{999, 999}})
+	}
+	b.ReportMetric(float64(result.Expanded), "expanded/op")
+}
+
+func BenchmarkHPAStarBuild1000x1000(b *testing.B) {
+	board, err := maze.GenerateRandom(1000, 1000, 0.25, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Build(board, DefaultClusterSize)
+	}
+}
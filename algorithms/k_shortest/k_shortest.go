@@ -0,0 +1,388 @@
+package k_shortest
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"slices"
+
+	"algo/algorithms"
+)
+
+// Имена в метриках
+const (
+	NameYen     = "k-shortest"
+	NameDiverse = "diverse-paths"
+)
+
+// DefaultPenalty доля, на которую дорожает вход в клетку после каждого пути, прошедшего через нее
+const DefaultPenalty = 1.0
+
+// maxDiverseRounds во сколько раз количество поисков в Diverse может превышать k: поиск после штрафа
+// может найти уже найденный путь, если обойти его дороже
+const maxDiverseRounds = 4
+
+// checkEvery количество раскрытий между проверками отмены контекста
+const checkEvery = 1024
+
+// grid лабиринт с нумерацией клеток x*cols+y. Цели конечны: путь заканчивается в первой достигнутой цели
+// и не проходит через другие цели. Рабочие массивы поиска выделяются один раз и используются всеми поисками
+// по сетке: номер поиска в stamp отличает значения текущего поиска от оставшихся с прошлых
+type grid struct {
+	ctx      context.Context
+	board    [][]bool
+	cols     int
+	isTarget []bool
+	h        []int32 // Расстояние до ближайшей цели без запретов, -1 — цели недостижимы
+	expanded int
+
+	stamp   []int32
+	run     int32
+	dist    []float64
+	parent  []int32
+	queue   distanceQueue
+	touched []int // Клетки, до которых дошел последний поиск
+	cut     bool  // Последний поиск отсек часть клеток по limit
+
+	// dead[cell] == round — из клетки не дойти до цели в текущем раунде Yen, nil вне Yen
+	dead  []int32
+	round int32
+}
+
+func newGrid(ctx context.Context, board [][]bool, targets [][2]int) *grid {
+	size := len(board) * len(board[0])
+	g := &grid{
+		ctx:      ctx,
+		board:    board,
+		cols:     len(board[0]),
+		isTarget: make([]bool, size),
+		h:        make([]int32, size),
+		stamp:    make([]int32, size),
+		dist:     make([]float64, size),
+		parent:   make([]int32, size),
+	}
+
+	// Обход в ширину одновременно из всех целей дает расстояние до ближайшей цели. Запреты поисков ответвлений и штрафы
+	// только удлиняют пути, а каждый шаг стоит не меньше 1, поэтому эта оценка допустима и согласована
+	for i := range g.h {
+		g.h[i] = -1
+	}
+	var queue []int
+	for _, target := range targets {
+		if cell := target[0]*g.cols + target[1]; algorithms.IsValid(board, target[0], target[1]) && !g.isTarget[cell] {
+			g.isTarget[cell] = true
+			g.h[cell] = 0
+			queue = append(queue, cell)
+		}
+	}
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		g.neighbors(current, func(next int) {
+			if g.h[next] == -1 {
+				g.h[next] = g.h[current] + 1
+				queue = append(queue, next)
+			}
+		})
+	}
+
+	return g
+}
+
+// neighbors вызывает visit для свободных соседей клетки
+func (g *grid) neighbors(cell int, visit func(next int)) {
+	x, y := cell/g.cols, cell%g.cols
+	for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+		if nx, ny := x+dir[0], y+dir[1]; algorithms.IsValid(g.board, nx, ny) {
+			visit(nx*g.cols + ny)
+		}
+	}
+}
+
+func (g *grid) result(path []int) algorithms.Result {
+	nodes := make([]algorithms.Node, len(path))
+	for i, cell := range path {
+		nodes[i] = algorithms.Node{X: cell / g.cols, Y: cell % g.cols, G: i}
+	}
+	return algorithms.Result{Dist: len(path) - 1, Path: nodes, Expanded: g.expanded}
+}
+
+// Yen возвращает до k кратчайших путей без повторяющихся клеток от старта до целей в порядке неубывания длины.
+// Каждый следующий путь ищется от каждой клетки предыдущего (точки ответвления): начало пути до нее сохраняется,
+// клетки начала и ребра, которыми из нее уже уходили найденные пути с тем же началом, запрещаются, а остаток
+// ищется A* с расстоянием до ближайшей цели в качестве оценки. Лучший из кандидатов становится следующим путем.
+// Когда кандидатов уже хватает на оставшиеся пути, поиск ответвления отсекает пути не короче худшего из нужных
+// кандидатов: такой путь все равно не попадет в ответ. Expanded каждого пути — количество клеток, раскрытых
+// с начала поиска до нахождения этого пути. При отмене контекста возвращает найденные к этому моменту пути
+// и ошибку контекста
+func Yen(ctx context.Context, board [][]bool, startX, startY int, targets [][2]int, k int) ([]algorithms.Result, error) {
+	if k <= 0 || len(targets) == 0 || !algorithms.IsValid(board, startX, startY) {
+		return nil, nil
+	}
+
+	g := newGrid(ctx, board, targets)
+	blocked := make([]bool, len(g.isTarget))
+	first := g.search(startX*g.cols+startY, blocked, nil, nil, math.Inf(1))
+	if first == nil {
+		return nil, ctx.Err()
+	}
+
+	found := [][]int{first}
+	results := []algorithms.Result{g.result(first)}
+	seen := map[string]bool{pathKey(first): true}
+	candidates := &candidateQueue{}
+
+	g.dead = make([]int32, len(g.isTarget))
+	for len(found) < k {
+		g.round++
+		bound := candidates.bound(k - len(found))
+		previous := found[len(found)-1]
+		// Длины общих начал найденных путей с предыдущим: путь уходит из точки ответвления i по запрещенному
+		// ребру, если совпадает с предыдущим в первых i+1 клетках
+		common := make([]int, len(found))
+		for j, path := range found {
+			for common[j] < min(len(path), len(previous)) && path[common[j]] == previous[common[j]] {
+				common[j]++
+			}
+		}
+		for i := 0; i < len(previous)-1; i++ {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			spur, root := previous[i], previous[:i+1]
+			if i > 0 {
+				blocked[previous[i-1]] = true
+			}
+
+			var bannedNext []int
+			for j, path := range found {
+				if len(path) > i+1 && common[j] >= i+1 {
+					bannedNext = append(bannedNext, path[i+1])
+				}
+			}
+			// Путь из i клеток начала и spurPath короче bound клеток, только если в spurPath меньше bound-i клеток
+			spurPath := g.search(spur, blocked, bannedNext, nil, float64(bound-i-1))
+
+			if spurPath == nil {
+				// Если поиск обошел все доступные клетки, из них не дойти до цели и от следующих точек ответвления:
+				// в их поисках заблокирована и эта точка, а запрещенные ребра выходят только из нее
+				if !g.cut && ctx.Err() == nil {
+					for _, cell := range g.touched {
+						g.dead[cell] = g.round
+					}
+				}
+				continue
+			}
+			path := append(slices.Clone(root[:i]), spurPath...)
+			if key := pathKey(path); !seen[key] {
+				seen[key] = true
+				heap.Push(candidates, candidate{path: path, order: candidates.pushed})
+				candidates.pushed++
+				bound = min(bound, candidates.bound(k-len(found)))
+			}
+		}
+		for _, cell := range previous {
+			blocked[cell] = false
+		}
+
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		if candidates.Len() == 0 {
+			break
+		}
+		next := heap.Pop(candidates).(candidate).path
+		found = append(found, next)
+		results = append(results, g.result(next))
+	}
+
+	return results, nil
+}
+
+// search ищет A* самый дешевый путь от клетки до ближайшей цели, не заходя в заблокированные клетки и не переходя
+// из начальной клетки в клетки bannedNext. cost — стоимость входа в клетку, nil — каждый шаг стоит 1. Пути,
+// стоимость которых не меньше limit, отсекаются. Возвращает nil, если пути нет или контекст отменен
+func (g *grid) search(from int, blocked []bool, bannedNext []int, cost []float64, limit float64) []int {
+	if g.isTarget[from] {
+		return []int{from}
+	}
+	g.touched, g.cut = g.touched[:0], float64(g.h[from]) >= limit
+	if g.h[from] == -1 || g.cut {
+		return nil
+	}
+
+	g.run++
+	g.stamp[from] = g.run
+	g.touched = append(g.touched, from)
+	g.dist[from] = 0
+	g.parent[from] = -1
+	g.queue = append(g.queue[:0], distanceItem{cell: from, dist: float64(g.h[from])})
+
+	for expanded := 1; g.queue.Len() > 0; expanded++ {
+		current := heap.Pop(&g.queue).(distanceItem)
+		if current.dist-float64(g.h[current.cell]) > g.dist[current.cell] {
+			continue
+		}
+		if expanded%checkEvery == 0 && g.ctx.Err() != nil {
+			return nil
+		}
+		g.expanded++
+
+		if g.isTarget[current.cell] {
+			var path []int
+			for cell := current.cell; cell != -1; cell = int(g.parent[cell]) {
+				path = append(path, cell)
+			}
+			slices.Reverse(path)
+			return path
+		}
+
+		g.neighbors(current.cell, func(next int) {
+			if blocked[next] || g.h[next] == -1 || (g.dead != nil && g.dead[next] == g.round) ||
+				(current.cell == from && slices.Contains(bannedNext, next)) {
+				return
+			}
+			step := 1.0
+			if cost != nil {
+				step = cost[next]
+			}
+			d := g.dist[current.cell] + step
+			if g.stamp[next] == g.run && d >= g.dist[next] {
+				return
+			}
+			if f := d + float64(g.h[next]); f >= limit {
+				g.cut = true
+			} else {
+				if g.stamp[next] != g.run {
+					g.touched = append(g.touched, next)
+				}
+				g.stamp[next] = g.run
+				g.dist[next] = d
+				g.parent[next] = int32(current.cell)
+				heap.Push(&g.queue, distanceItem{cell: next, dist: f})
+			}
+		})
+	}
+
+	return nil
+}
+
+// Diverse возвращает до k различных путей методом штрафов: после каждого найденного пути вход в его клетки
+// дорожает в 1+penalty раз, и следующий путь ищется A* по новым стоимостям. Пути получаются непохожими друг
+// на друга, но, в отличие от Yen, не обязательно идут по возрастанию длины. Dist — число шагов пути.
+// При отмене контекста возвращает найденные к этому моменту пути и ошибку контекста
+func Diverse(ctx context.Context, board [][]bool, startX, startY int, targets [][2]int, k int, penalty float64) ([]algorithms.Result, error) {
+	if k <= 0 || len(targets) == 0 || !algorithms.IsValid(board, startX, startY) {
+		return nil, nil
+	}
+
+	g := newGrid(ctx, board, targets)
+	blocked := make([]bool, len(g.isTarget))
+	cost := make([]float64, len(g.isTarget))
+	for i := range cost {
+		cost[i] = 1
+	}
+
+	var results []algorithms.Result
+	seen := make(map[string]bool)
+	for round := 0; len(results) < k && round < k*maxDiverseRounds; round++ {
+		path := g.search(startX*g.cols+startY, blocked, nil, cost, math.Inf(1))
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		if path == nil {
+			break
+		}
+		if key := pathKey(path); !seen[key] {
+			seen[key] = true
+			results = append(results, g.result(path))
+		}
+		for _, cell := range path[1:] {
+			cost[cell] *= 1 + penalty
+		}
+	}
+
+	return results, nil
+}
+
+// pathKey ключ пути для отбрасывания повторов
+func pathKey(path []int) string {
+	key := make([]byte, 0, len(path)*4)
+	for _, cell := range path {
+		key = append(key, byte(cell), byte(cell>>8), byte(cell>>16), byte(cell>>24))
+	}
+	return string(key)
+}
+
+// candidate путь-кандидат Yen, order сохраняет порядок добавления для путей одной длины
+type candidate struct {
+	path  []int
+	order int
+}
+
+// candidateQueue очередь кандидатов по длине пути
+type candidateQueue struct {
+	items  []candidate
+	pushed int
+}
+
+// bound возвращает количество клеток needed-го по длине кандидата: кандидат той же или большей длины,
+// добавленный позже, в ответ не попадет. Если кандидатов меньше needed, ограничения нет
+func (q *candidateQueue) bound(needed int) int {
+	if len(q.items) < needed {
+		return math.MaxInt
+	}
+	lengths := make([]int, len(q.items))
+	for i, item := range q.items {
+		lengths[i] = len(item.path)
+	}
+	slices.Sort(lengths)
+	return lengths[needed-1]
+}
+
+func (q *candidateQueue) Len() int { return len(q.items) }
+
+func (q *candidateQueue) Less(i, j int) bool {
+	if len(q.items[i].path) != len(q.items[j].path) {
+		return len(q.items[i].path) < len(q.items[j].path)
+	}
+	return q.items[i].order < q.items[j].order
+}
+
+func (q *candidateQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *candidateQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(candidate))
+}
+
+func (q *candidateQueue) Pop() interface{} {
+	n := len(q.items)
+	it := q.items[n-1]
+	q.items = q.items[0 : n-1]
+	return it
+}
+
+type distanceItem struct {
+	cell int
+	dist float64
+}
+
+// distanceQueue очередь приоритетов по оценке стоимости пути через клетку: стоимость до нее плюс эвристика
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int { return len(q) }
+
+func (q distanceQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+
+func (q distanceQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *distanceQueue) Push(x interface{}) {
+	*q = append(*q, x.(distanceItem))
+}
+
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[0 : n-1]
+	return it
+}
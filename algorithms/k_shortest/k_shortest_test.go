@@ -0,0 +1,247 @@
+package k_shortest
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"algo/algorithms"
+	"algo/maze"
+)
+
+// checkPaths проверяет, что пути различны, состоят из соседних свободных клеток без повторов
+// и заканчиваются в первой достигнутой цели
+func checkPaths(t *testing.T, board [][]bool, start [2]int, targets [][2]int, results []algorithms.Result) {
+	t.Helper()
+
+	isTarget := func(node algorithms.Node) bool {
+		return slices.Contains(targets, [2]int{node.X, node.Y})
+	}
+	for i, result := range results {
+		path := result.Path
+		if result.Dist != len(path)-1 {
+			t.Fatalf("path %d: dist %d, %d nodes", i, result.Dist, len(path))
+		}
+		if path[0].X != start[0] || path[0].Y != start[1] || !isTarget(path[len(path)-1]) {
+			t.Fatalf("path %d goes from %v to %v", i, path[0], path[len(path)-1])
+		}
+		seen := make(map[[2]int]bool)
+		for j, node := range path {
+			if !algorithms.IsValid(board, node.X, node.Y) || seen[[2]int{node.X, node.Y}] {
+				t.Fatalf("path %d: node %d (%d, %d) is wall or repeated", i, j, node.X, node.Y)
+			}
+			seen[[2]int{node.X, node.Y}] = true
+			if j > 0 && abs(node.X-path[j-1].X)+abs(node.Y-path[j-1].Y) != 1 {
+				t.Fatalf("path %d: nodes %d and %d are not adjacent", i, j-1, j)
+			}
+			if j < len(path)-1 && isTarget(node) {
+				t.Fatalf("path %d passes through target (%d, %d)", i, node.X, node.Y)
+			}
+		}
+		for _, previous := range results[:i] {
+			if slices.EqualFunc(previous.Path, path, func(a, b algorithms.Node) bool { return a.X == b.X && a.Y == b.Y }) {
+				t.Fatalf("path %d repeats an earlier path", i)
+			}
+		}
+	}
+}
+
+// simplePathLengths перебирает все пути без повторяющихся клеток до первой достигнутой цели и возвращает их длины
+func simplePathLengths(board [][]bool, start [2]int, targets [][2]int) []int {
+	var lengths []int
+	visited := make(map[[2]int]bool)
+	var walk func(cell [2]int, length int)
+	walk = func(cell [2]int, length int) {
+		if slices.Contains(targets, cell) {
+			lengths = append(lengths, length)
+			return
+		}
+		visited[cell] = true
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			next := [2]int{cell[0] + dir[0], cell[1] + dir[1]}
+			if algorithms.IsValid(board, next[0], next[1]) && !visited[next] {
+				walk(next, length+1)
+			}
+		}
+		visited[cell] = false
+	}
+	walk(start, 0)
+
+	slices.Sort(lengths)
+	return lengths
+}
+
+func TestYenMatchesEnumeration(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		board, err := maze.GenerateRandom(2+rnd.Intn(4), 2+rnd.Intn(4), rnd.Float64()*0.4, rnd.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := [2]int{rnd.Intn(len(board)), rnd.Intn(len(board[0]))}
+		targets := [][2]int{{rnd.Intn(len(board)), rnd.Intn(len(board[0]))}, {rnd.Intn(len(board)), rnd.Intn(len(board[0]))}}
+		if !algorithms.IsValid(board, start[0], start[1]) {
+			continue
+		}
+
+		want := simplePathLengths(board, start, targets)
+		results, err := Yen(context.Background(), board, start[0], start[1], targets, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPaths(t, board, start, targets, results)
+		if len(results) != min(len(want), 10) {
+			t.Fatalf("board %d: got %d paths, %d simple paths exist", i, len(results), len(want))
+		}
+		for j, result := range results {
+			if result.Dist != want[j] {
+				t.Fatalf("board %d: path %d dist %d, want %d (all lengths %v)", i, j, result.Dist, want[j], want)
+			}
+		}
+	}
+}
+
+func TestYenOnLabyrinth(t *testing.T) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41_many_targets.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := [2]int{1, 0}
+	targets := algorithms.GetBoundaryCells(board, start[0], start[1])
+
+	// В лабиринте без циклов до каждого выхода ведет ровно один путь, поэтому путей столько, сколько достижимых выходов
+	results, err := Yen(context.Background(), board, start[0], start[1], targets, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPaths(t, board, start, targets, results)
+	if len(results) != 4 {
+		t.Fatalf("got %d paths, want 4", len(results))
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Dist < results[i-1].Dist {
+			t.Errorf("path %d dist %d is shorter than path %d dist %d", i, results[i].Dist, i-1, results[i-1].Dist)
+		}
+	}
+}
+
+func TestDiverse(t *testing.T) {
+	board := make([][]bool, 10)
+	for i := range board {
+		board[i] = make([]bool, 10)
+	}
+	start, targets := [2]int{0, 0}, [][2]int{{9, 9}}
+
+	results, err := Diverse(context.Background(), board, start[0], start[1], targets, 4, DefaultPenalty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPaths(t, board, start, targets, results)
+	if len(results) != 4 {
+		t.Fatalf("got %d paths, want 4", len(results))
+	}
+	if results[0].Dist != 18 {
+		t.Errorf("first path dist %d, want shortest 18", results[0].Dist)
+	}
+
+	// Штраф разводит пути: второй путь делит с первым меньше половины клеток
+	shared := 0
+	for _, node := range results[1].Path {
+		if slices.ContainsFunc(results[0].Path, func(other algorithms.Node) bool { return other.X == node.X && other.Y == node.Y }) {
+			shared++
+		}
+	}
+	if shared*2 >= len(results[1].Path) {
+		t.Errorf("second path shares %d of %d cells with the first", shared, len(results[1].Path))
+	}
+
+	if got, _ := Diverse(context.Background(), board, start[0], start[1], [][2]int{start}, 3, DefaultPenalty); len(got) != 1 {
+		t.Errorf("start at target: got %d paths, want 1", len(got))
+	}
+}
+
+// openBoard строит случайную доску 401x401, в которой угол (400, 400) соединен с остальной доской
+func openBoard(t *testing.T) [][]bool {
+	t.Helper()
+
+	board, err := maze.GenerateRandom(401, 401, 0.2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range [][2]int{{399, 399}, {399, 400}, {400, 399}, {400, 400}} {
+		board[cell[0]][cell[1]] = false
+	}
+	return board
+}
+
+func TestYenOnLargeBoards(t *testing.T) {
+	labyrinth, err := maze.Generate(401, 401, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		board   [][]bool
+		start   [2]int
+		targets [][2]int
+		want    int
+	}{
+		// В идеальном лабиринте путь один, и поиски ответвлений от каждой его клетки ничего не находят:
+		// клетки, из которых не дойти до цели, не обходятся повторно
+		{name: "labyrinth", board: labyrinth, start: [2]int{1, 0}, targets: [][2]int{{399, 400}}, want: 1},
+		// Поиски ответвлений отсекают пути не короче худшего из нужных кандидатов
+		{name: "random", board: openBoard(t), start: [2]int{0, 0}, targets: [][2]int{{400, 400}}, want: 10},
+	} {
+		startTime := time.Now()
+		results, err := Yen(context.Background(), test.board, test.start[0], test.start[1], test.targets, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+			t.Errorf("%s: 10 paths on 401x401 took %s", test.name, elapsed)
+		}
+		checkPaths(t, test.board, test.start, test.targets, results)
+		if len(results) != test.want {
+			t.Fatalf("%s: got %d paths, want %d", test.name, len(results), test.want)
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Dist < results[i-1].Dist {
+				t.Errorf("%s: path %d dist %d is shorter than path %d dist %d", test.name, i, results[i].Dist, i-1, results[i-1].Dist)
+			}
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	board := openBoard(t)
+	targets := [][2]int{{400, 400}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	results, err := Yen(ctx, board, 0, 0, targets, 10000)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got %d paths and error %v, want deadline exceeded", len(results), err)
+	}
+	if elapsed := time.Since(startTime); elapsed > time.Second {
+		t.Errorf("cancelled search returned after %s", elapsed)
+	}
+	checkPaths(t, board, [2]int{0, 0}, targets, results)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = Diverse(cancelled, board, 0, 0, targets, 10, DefaultPenalty); err != context.Canceled {
+		t.Errorf("diverse: got error %v, want canceled", err)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -2,16 +2,17 @@ package lazy_theta_star
 
 import (
 	"container/heap"
-	"log"
 	"math"
-	"os"
-	"time"
+	"slices"
 
 	"algo/algorithms"
-	"algo/maze"
-	"github.com/pkg/errors"
+	"algo/algorithms/heuristics"
 )
 
+func init() {
+	algorithms.Register(algorithms.Algorithm{ID: 2, Name: "lazy-theta", Title: "Lazy Theta*", Solve: LazyThetaStar, AnyAngle: true})
+}
+
 // PriorityQueue реализует очередь приоритетов для узлов
 type PriorityQueue []*algorithms.Node
 
@@ -44,202 +45,137 @@ func (pq *PriorityQueue) Pop() interface{} {
 	return item
 }
 
-// heuristic возвращает эвристическое значение между двумя узлами
+// heuristic возвращает расстояние по Манхэттену между двумя узлами, им же измеряется длина отрезка пути
 func heuristic(a, b *algorithms.Node) int {
-	return int(math.Abs(float64(a.X-b.X)) + math.Abs(float64(a.Y-b.Y)))
-}
-
-// isInClosedList проверяет, находится ли узел в закрытом списке
-func isInClosedList(closedList map[[2]int]bool, node *algorithms.Node) bool {
-	_, found := closedList[[2]int{node.X, node.Y}]
-	return found
-}
-
-// isInOpenList проверяет, находится ли узел в открытом списке
-func isInOpenList(openList map[[2]int]*algorithms.Node, node *algorithms.Node) bool {
-	_, found := openList[[2]int{node.X, node.Y}]
-	return found
+	return heuristics.Manhattan.Between(a.X, a.Y, b.X, b.Y)
 }
 
 // reconstructPath восстанавливает путь от целевого узла до стартового
 func reconstructPath(current *algorithms.Node) []algorithms.Node {
 	path := make([]algorithms.Node, 0)
 	for current != nil {
-		path = append([]algorithms.Node{*current}, path...)
+		path = append(path, *current)
 		current = current.VParent
 	}
+	slices.Reverse(path)
 	return path
 }
 
-// lineOfSight проверяет, есть ли прямая видимость между двумя узлами
+// lineOfSight проверяет, есть ли прямая видимость между двумя узлами: все клетки, которые пересекает отрезок
+// между их центрами, свободны. Если отрезок проходит ровно через угол, свободны должны быть обе клетки у этого угла,
+// иначе путь срезал бы угол стены. Клетки отрезка образуют путь по сторонам длиной в его расстояние по Манхэттену,
+// поэтому отрезок не короче пути по сетке
 func lineOfSight(board [][]bool, start, end *algorithms.Node) bool {
 	if start == nil || end == nil {
 		return false
 	}
 
-	x0, y0 := start.X, start.Y
-	x1, y1 := end.X, end.Y
-	dx, dy := x1-x0, y1-y0
-	f := 0
-
-	if dx < 0 {
-		dx = -dx
-	}
-	if dy < 0 {
-		dy = -dy
-	}
-
-	if dx >= dy {
-		var y int
-		if dy == 0 {
-			y = y0
-		} else {
-			y = y0 + dy*(f+dx/2)/dx
-		}
-		for x := x0; x <= x1; x++ {
-			if x != x0 || y != y0 {
-				if !algorithms.IsValid(board, x, y) {
-					return false
-				}
+	dx, dy := abs(end.X-start.X), abs(end.Y-start.Y)
+	sx, sy := sign(end.X-start.X), sign(end.Y-start.Y)
+
+	x, y := start.X, start.Y
+	for ix, iy := 0, 0; ix < dx || iy < dy; {
+		// Какую границу клетки отрезок пересекает раньше: (1+2*ix)/dx против (1+2*iy)/dy
+		decision := (1+2*ix)*dy - (1+2*iy)*dx
+		switch {
+		case decision == 0:
+			if !algorithms.IsValid(board, x+sx, y) || !algorithms.IsValid(board, x, y+sy) {
+				return false
 			}
-			f = f + dy
-			if f >= dx {
-				y = y + 1
-				if (dy < 0 && y > y1) || (dy > 0 && y < y1) {
-					return false
-				}
-				f = f - dx
-			}
-		}
-	} else {
-		var x int
-		if dx == 0 {
-			x = x0
-		} else {
-			x = x0 + dx*(f+dy/2)/dy
+			x, y = x+sx, y+sy
+			ix, iy = ix+1, iy+1
+		case decision < 0:
+			x += sx
+			ix++
+		default:
+			y += sy
+			iy++
 		}
-		for y := y0; y <= y1; y++ {
-			if x != x0 || y != y0 {
-				if !algorithms.IsValid(board, x, y) {
-					return false
-				}
-			}
-			f = f + dx
-			if f >= dy {
-				x = x + 1
-				if (dx < 0 && x > x1) || (dx > 0 && x < x1) {
-					return false
-				}
-				f = f - dy
-			}
+		if !algorithms.IsValid(board, x, y) {
+			return false
 		}
 	}
 	return true
 }
 
-// updateVertex обновляет значение узла
-func updateVertex(openList *PriorityQueue, openListMap map[[2]int]*algorithms.Node, board [][]bool, node *algorithms.Node, neighbor *algorithms.Node) {
-	if node.VParent == nil {
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// updateVertex улучшает путь до соседа через видимого родителя текущего узла или через сам текущий узел.
+// Сосед, который уже в открытом списке, обновляется на месте, только если путь до него стал короче,
+// новый сосед добавляется в открытый список
+func updateVertex(openList *PriorityQueue, openListMap map[[2]int]*algorithms.Node, board [][]bool, targets [][2]int, node *algorithms.Node, x, y int) {
+	neighbor, open := openListMap[[2]int{x, y}]
+	if !open {
+		neighbor = &algorithms.Node{X: x, Y: y, G: math.MaxInt32}
+	}
+
+	parent := node
+	if node.VParent != nil && lineOfSight(board, node.VParent, neighbor) {
+		parent = node.VParent
+	}
+	newG := parent.G + heuristic(parent, neighbor)
+	if newG >= neighbor.G {
 		return
 	}
 
-	if lineOfSight(board, node.VParent, neighbor) {
-		newG := node.VParent.G + heuristic(node.VParent, neighbor)
-		if newG < neighbor.G {
-			neighbor.G = newG
-			neighbor.F = newG + heuristic(neighbor, startNode)
-			neighbor.VParent = node.VParent
-			heap.Fix(openList, neighbor.Index)
-		}
+	neighbor.G = newG
+	neighbor.F = newG + heuristics.ToNearest(heuristics.Manhattan, neighbor.X, neighbor.Y, targets)
+	neighbor.VParent = parent
+	if open {
+		heap.Fix(openList, neighbor.Index)
 	} else {
-		if neighbor.G > node.G+heuristic(node, neighbor) {
-			neighbor.G = node.G + heuristic(node, neighbor)
-			neighbor.F = neighbor.G + heuristic(neighbor, startNode)
-			neighbor.VParent = node
-			heap.Fix(openList, neighbor.Index)
-		}
+		heap.Push(openList, neighbor)
+		openListMap[[2]int{x, y}] = neighbor
 	}
 }
 
-var startNode *algorithms.Node
-
 // LazyThetaStar алгоритм поиска кратчайшего пути
-func LazyThetaStar(board [][]bool, startX, startY int, targets [][2]int) (int, []algorithms.Node) {
+func LazyThetaStar(board [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
 	openList := &PriorityQueue{}
 	heap.Init(openList)
 	closedList := make(map[[2]int]bool)
-	startNode = &algorithms.Node{X: startX, Y: startY, G: 0, H: 0, F: 0, VParent: nil, Index: 0}
+	startNode := &algorithms.Node{X: startX, Y: startY, G: 0, H: 0, F: 0, VParent: nil, Index: 0}
 	heap.Push(openList, startNode)
 	openListMap := make(map[[2]int]*algorithms.Node)
 	openListMap[[2]int{startNode.X, startNode.Y}] = startNode
 
+	expanded := 0
 	for openList.Len() > 0 {
 		current := heap.Pop(openList).(*algorithms.Node)
+		expanded++
 		delete(openListMap, [2]int{current.X, current.Y})
 		closedList[[2]int{current.X, current.Y}] = true
 
 		for _, target := range targets {
 			if current.X == target[0] && current.Y == target[1] {
-				return current.G, reconstructPath(current)
+				return algorithms.Result{Dist: current.G, Path: reconstructPath(current), Expanded: expanded}
 			}
 		}
 
 		neighbors := [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}
 		for _, dir := range neighbors {
-			neighbor := &algorithms.Node{X: current.X + dir[0], Y: current.Y + dir[1]}
-			if !algorithms.IsValid(board, neighbor.X, neighbor.Y) {
+			x, y := current.X+dir[0], current.Y+dir[1]
+			if !algorithms.IsValid(board, x, y) || closedList[[2]int{x, y}] {
 				continue
 			}
-
-			if !isInClosedList(closedList, neighbor) {
-				if !isInOpenList(openListMap, neighbor) {
-					neighbor.G = math.MaxInt32
-				}
-
-				if neighbor.G > current.G+heuristic(current, neighbor) {
-					neighbor.G = current.G + heuristic(current, neighbor)
-					neighbor.F = neighbor.G + heuristic(neighbor, startNode)
-					neighbor.VParent = current
-					heap.Fix(openList, neighbor.Index)
-				}
-
-				if !isInOpenList(openListMap, neighbor) {
-					heap.Push(openList, neighbor)
-					openListMap[[2]int{neighbor.X, neighbor.Y}] = neighbor
-				}
-
-				updateVertex(openList, openListMap, board, current, neighbor)
-			}
+			updateVertex(openList, openListMap, board, targets, current, x, y)
 		}
 	}
 
-	return -1, nil
-}
-
-// TestLazyThetaStar тестирует алгоритм Lazy Theta*
-func TestLazyThetaStar() {
-	board, err := maze.ParseMaze(os.Getenv("MAZE_FILE_2"))
-	if err != nil {
-		log.Fatal(errors.Wrap(err, "failed to parse maze"))
-	}
-
-	startX, startY := 1, 0
-	boundaryCells := algorithms.GetBoundaryCells(board, startX, startY)
-	if len(boundaryCells) == 0 {
-		log.Println("Нет доступных выходных клеток на границе")
-		return
-	}
-
-	startTime := time.Now()
-	distance, path := LazyThetaStar(board, startX, startY, boundaryCells)
-	endTime := time.Now()
-	log.Printf("Время работы: %d\n", endTime.Sub(startTime))
-
-	if distance != -1 {
-		log.Printf("Кратчайшее расстояние до выхода: %d\n", distance)
-		log.Println("Лабиринт с выделенным путем:")
-		algorithms.PrintBoard(board, path, boundaryCells, startX, startY)
-	} else {
-		log.Println("Путь до выхода не найден")
-	}
+	return algorithms.NotFound(expanded)
 }
@@ -0,0 +1,63 @@
+package lazy_theta_star
+
+import (
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+func TestLineOfSight(t *testing.T) {
+	// Стена в (1, 1) закрывает (0, 1) от (2, 1) и угол между (0, 1) и (1, 2), остальные клетки свободны
+	board := [][]bool{
+		{false, false, false},
+		{false, true, false},
+		{false, false, false},
+	}
+	node := func(x, y int) *algorithms.Node { return &algorithms.Node{X: x, Y: y} }
+
+	for _, test := range []struct {
+		from, to *algorithms.Node
+		want     bool
+	}{
+		{from: node(2, 1), to: node(0, 1), want: false},
+		{from: node(1, 2), to: node(1, 0), want: false},
+		{from: node(2, 2), to: node(0, 0), want: false},
+		{from: node(2, 0), to: node(0, 2), want: false},
+		{from: node(0, 1), to: node(1, 2), want: false}, // Отрезок проходит через угол стены
+		{from: node(2, 0), to: node(0, 0), want: true},
+		{from: node(0, 2), to: node(0, 0), want: true},
+		{from: node(2, 2), to: node(2, 0), want: true},
+	} {
+		for _, pair := range [][2]*algorithms.Node{{test.from, test.to}, {test.to, test.from}} {
+			if got := lineOfSight(board, pair[0], pair[1]); got != test.want {
+				t.Errorf("line of sight from (%d, %d) to (%d, %d): got %t, want %t", pair[0].X, pair[0].Y, pair[1].X, pair[1].Y, got, test.want)
+			}
+		}
+	}
+}
+
+func BenchmarkLazyThetaStarLabyrinth41x41(b *testing.B) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	testboards.Benchmark(b, LazyThetaStar, board, 1, 0, algorithms.GetBoundaryCells(board, 1, 0))
+}
+
+func BenchmarkLazyThetaStarLabyrinth41x41ManyTargets(b *testing.B) {
+	board, err := maze.ParseMaze("../../maze/labyrinth_matrix_41x41_many_targets.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	testboards.Benchmark(b, LazyThetaStar, board, 1, 0, algorithms.GetBoundaryCells(board, 1, 0))
+}
+
+func BenchmarkLazyThetaStarGenerated401x401(b *testing.B) {
+	testboards.BenchmarkGenerated401x401(b, LazyThetaStar)
+}
+
+func BenchmarkLazyThetaStarRandom1000x1000(b *testing.B) {
+	testboards.BenchmarkRandom1000x1000(b, LazyThetaStar)
+}
@@ -0,0 +1,143 @@
+package algorithms
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"algo/algorithms/heuristics"
+)
+
+// Result представляет собой результат работы алгоритма поиска пути
+type Result struct {
+	Dist     int
+	Path     []Node
+	Expanded int            // Количество раскрытых узлов
+	Stats    map[string]int // Дополнительная статистика, которую сообщает конкретный алгоритм
+}
+
+// NotFound возвращает результат для случая, когда путь не найден
+func NotFound(expanded int) Result {
+	return Result{Dist: PathNotFound, Expanded: expanded}
+}
+
+// Solver ищет кратчайший путь от стартовой клетки до любой из целевых клеток
+type Solver func(board [][]bool, startX, startY int, targets [][2]int) Result
+
+// Options параметры поиска, заданные в запросе
+type Options struct {
+	Weight    float64              // Вес эвристики не меньше 1, 0 — без веса
+	Heuristic heuristics.Heuristic // Эвристика, nil — эвристика модели перемещения по умолчанию
+	Movement  heuristics.Movement  // Модель перемещения, пустая — модель алгоритма по умолчанию
+}
+
+// Index вспомогательная структура, которую алгоритм строит по лабиринту и переиспользует между запросами
+type Index interface {
+	// Solve ищет путь в лабиринте, по которому построен индекс
+	Solve(startX, startY int, targets [][2]int) Result
+	// Update возвращает индекс для измененного лабиринта, перестраивая только затронутые изменением части.
+	// Исходный индекс не меняется и может использоваться параллельно
+	Update(board [][]bool) Index
+}
+
+// Algorithm описывает зарегистрированный алгоритм поиска пути
+type Algorithm struct {
+	ID    int    // Идентификатор, используемый в API как algorithm_id
+	Name  string // Короткое имя для CLI и отчетов
+	Title string // Человекочитаемое название
+	Solve Solver
+
+	// AnyAngle означает, что путь состоит из вершин, между которыми есть прямая видимость,
+	// а не из соседних клеток. Расстояние между соседними вершинами пути считается по Манхэттену
+	AnyAngle bool
+
+	// Approximate означает, что найденный путь может быть длиннее кратчайшего
+	Approximate bool
+
+	// TimeBounded означает, что решатель ограничен временем работы и для одной задачи может вернуть разные пути,
+	// поэтому его результаты не кэшируются
+	TimeBounded bool
+
+	// Movement модель перемещения, для которой проверяется допустимость выбранной эвристики, по умолчанию heuristics.Grid4
+	Movement heuristics.Movement
+
+	// Configure возвращает решатель с параметрами запроса. Weighted означает, что решатель учитывает вес эвристики:
+	// при весе w путь может быть длиннее кратчайшего не более чем в w раз. Heuristics означает, что решатель
+	// использует выбранную эвристику, Movements — что решатель ходит по выбранной модели перемещения
+	Configure  func(options Options) Solver
+	Weighted   bool
+	Heuristics bool
+	Movements  bool
+
+	// Preprocess строит индекс по лабиринту. Сервис строит индекс один раз для версии лабиринта
+	// и обновляет его при изменении клеток, Solve при этом используется для разовых запросов
+	Preprocess func(board [][]bool) Index
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[int]Algorithm)
+)
+
+// Register регистрирует алгоритм, вызывается из init() пакета с реализацией
+func Register(algorithm Algorithm) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if algorithm.Solve == nil {
+		panic(fmt.Sprintf("algorithms: Register solver is nil for %q", algorithm.Name))
+	}
+	if (algorithm.Weighted || algorithm.Heuristics || algorithm.Movements) && algorithm.Configure == nil {
+		panic(fmt.Sprintf("algorithms: Register Configure is nil for %q with search options", algorithm.Name))
+	}
+	if len(algorithm.Movement.Moves) == 0 {
+		algorithm.Movement = heuristics.Grid4
+	}
+	if _, found := registry[algorithm.ID]; found {
+		panic(fmt.Sprintf("algorithms: Register called twice for id %d", algorithm.ID))
+	}
+	for _, registered := range registry {
+		if registered.Name == algorithm.Name {
+			panic(fmt.Sprintf("algorithms: Register called twice for name %q", algorithm.Name))
+		}
+	}
+
+	registry[algorithm.ID] = algorithm
+}
+
+// Get возвращает алгоритм по идентификатору
+func Get(id int) (Algorithm, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	algorithm, found := registry[id]
+	return algorithm, found
+}
+
+// GetByName возвращает алгоритм по короткому имени
+func GetByName(name string) (Algorithm, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, algorithm := range registry {
+		if algorithm.Name == name {
+			return algorithm, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// All возвращает все зарегистрированные алгоритмы, упорядоченные по идентификатору
+func All() []Algorithm {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	result := make([]Algorithm, 0, len(registry))
+	for _, algorithm := range registry {
+		result = append(result, algorithm)
+	}
+	slices.SortFunc(result, func(a, b Algorithm) int {
+		return a.ID - b.ID
+	})
+	return result
+}
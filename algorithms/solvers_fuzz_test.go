@@ -0,0 +1,51 @@
+package algorithms_test
+
+import (
+	"testing"
+
+	"algo/algorithms"
+)
+
+// FuzzSolvers строит доску из произвольных байтов и проверяет контракт каждого алгоритма
+func FuzzSolvers(f *testing.F) {
+	f.Add(uint8(3), uint8(3), []byte{0, 0, 0, 0, 1, 0, 0, 0, 0}, uint16(0), uint16(8), false)
+	f.Add(uint8(5), uint8(4), []byte{0, 1, 0, 0, 0, 1, 0, 1, 0, 0, 0, 1, 1, 1, 0, 0, 0, 0, 0, 0}, uint16(0), uint16(19), true)
+	f.Add(uint8(1), uint8(1), []byte{0}, uint16(0), uint16(0), false)
+
+	f.Fuzz(func(t *testing.T, rows, cols uint8, cells []byte, start, target uint16, boundary bool) {
+		r, c := int(rows)%32+1, int(cols)%32+1
+
+		board := make([][]bool, r)
+		for i := range board {
+			board[i] = make([]bool, c)
+			for j := range board[i] {
+				if k := i*c + j; k < len(cells) {
+					board[i][j] = cells[k]%3 == 1
+				}
+			}
+		}
+
+		startCell := [2]int{int(start) % (r * c) / c, int(start) % c}
+		board[startCell[0]][startCell[1]] = false
+
+		var targets [][2]int
+		if boundary {
+			targets = algorithms.GetBoundaryCells(board, startCell[0], startCell[1])
+		} else {
+			targetCell := [2]int{int(target) % (r * c) / c, int(target) % c}
+			board[targetCell[0]][targetCell[1]] = false
+			targets = [][2]int{targetCell}
+		}
+
+		for _, algorithm := range algorithms.All() {
+			result := algorithm.Solve(board, startCell[0], startCell[1], targets)
+			if len(targets) == 0 {
+				if result.Dist != algorithms.PathNotFound {
+					t.Fatalf("%s: found path without targets", algorithm.Name)
+				}
+				continue
+			}
+			checkResult(t, algorithm, board, startCell, targets, result)
+		}
+	})
+}
@@ -0,0 +1,438 @@
+package algorithms_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"algo/algorithms"
+	_ "algo/algorithms/a_star"
+	_ "algo/algorithms/alt"
+	_ "algo/algorithms/ara_star"
+	_ "algo/algorithms/bidirectional_a_star"
+	_ "algo/algorithms/bidirectional_bfs"
+	_ "algo/algorithms/dijkstra"
+	_ "algo/algorithms/greedy_best_first"
+	"algo/algorithms/heuristics"
+	_ "algo/algorithms/hpa_star"
+	_ "algo/algorithms/lazy_theta_star"
+	"algo/algorithms/testboards"
+	"algo/maze"
+)
+
+// bfs возвращает длину кратчайшего пути по соседним клеткам или PathNotFound
+func bfs(board [][]bool, start [2]int, targets [][2]int) int {
+	isTarget := make(map[[2]int]bool, len(targets))
+	for _, target := range targets {
+		isTarget[target] = true
+	}
+
+	dist := map[[2]int]int{start: 0}
+	queue := [][2]int{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if isTarget[current] {
+			return dist[current]
+		}
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			next := [2]int{current[0] + dir[0], current[1] + dir[1]}
+			if _, seen := dist[next]; seen || !algorithms.IsValid(board, next[0], next[1]) {
+				continue
+			}
+			dist[next] = dist[current] + 1
+			queue = append(queue, next)
+		}
+	}
+
+	return algorithms.PathNotFound
+}
+
+// lineOfSight проверяет, что все клетки, которые пересекает отрезок между центрами клеток a и b, свободны.
+// Если отрезок проходит ровно через угол, обе клетки у этого угла должны быть свободны
+func lineOfSight(board [][]bool, a, b [2]int) bool {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	stepX, stepY := sign(dx), sign(dy)
+	dx, dy = abs(dx), abs(dy)
+
+	x, y := a[0], a[1]
+	if !algorithms.IsValid(board, x, y) {
+		return false
+	}
+	for ix, iy := 0, 0; ix < dx || iy < dy; {
+		// Сравниваем, какую границу клетки отрезок пересекает раньше: (1+2*ix)/dx против (1+2*iy)/dy
+		decision := (1+2*ix)*dy - (1+2*iy)*dx
+		switch {
+		case decision == 0:
+			if !algorithms.IsValid(board, x+stepX, y) || !algorithms.IsValid(board, x, y+stepY) {
+				return false
+			}
+			x, y = x+stepX, y+stepY
+			ix, iy = ix+1, iy+1
+		case decision < 0:
+			x += stepX
+			ix++
+		default:
+			y += stepY
+			iy++
+		}
+		if !algorithms.IsValid(board, x, y) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// checkResult проверяет контракт алгоритма на одной задаче
+func checkResult(t *testing.T, algorithm algorithms.Algorithm, board [][]bool, start [2]int, targets [][2]int, result algorithms.Result) {
+	t.Helper()
+
+	optimal := bfs(board, start, targets)
+	if optimal == algorithms.PathNotFound {
+		if result.Dist != algorithms.PathNotFound || len(result.Path) != 0 {
+			t.Fatalf("%s: expected no path, got dist=%d and %d nodes", algorithm.Name, result.Dist, len(result.Path))
+		}
+		return
+	}
+
+	if result.Dist == algorithms.PathNotFound {
+		t.Fatalf("%s: path not found, bfs dist=%d", algorithm.Name, optimal)
+	}
+	if len(result.Path) == 0 {
+		t.Fatalf("%s: empty path with dist=%d", algorithm.Name, result.Dist)
+	}
+
+	first, last := result.Path[0], result.Path[len(result.Path)-1]
+	if first.X != start[0] || first.Y != start[1] {
+		t.Fatalf("%s: path starts at (%d,%d), want (%d,%d)", algorithm.Name, first.X, first.Y, start[0], start[1])
+	}
+	isTarget := false
+	for _, target := range targets {
+		isTarget = isTarget || (last.X == target[0] && last.Y == target[1])
+	}
+	if !isTarget {
+		t.Fatalf("%s: path ends at (%d,%d), which is not a target", algorithm.Name, last.X, last.Y)
+	}
+
+	length := 0
+	for i, node := range result.Path {
+		if !algorithms.IsValid(board, node.X, node.Y) {
+			t.Fatalf("%s: path node %d (%d,%d) is a wall or out of bounds", algorithm.Name, i, node.X, node.Y)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := result.Path[i-1]
+		step := abs(node.X-prev.X) + abs(node.Y-prev.Y)
+		length += step
+		if algorithm.AnyAngle {
+			if !lineOfSight(board, [2]int{prev.X, prev.Y}, [2]int{node.X, node.Y}) {
+				t.Fatalf("%s: no line of sight between (%d,%d) and (%d,%d)", algorithm.Name, prev.X, prev.Y, node.X, node.Y)
+			}
+		} else if step != 1 {
+			t.Fatalf("%s: path is not contiguous between (%d,%d) and (%d,%d)", algorithm.Name, prev.X, prev.Y, node.X, node.Y)
+		}
+	}
+
+	if result.Dist != length {
+		t.Fatalf("%s: dist=%d, but path length is %d", algorithm.Name, result.Dist, length)
+	}
+	if result.Dist < optimal {
+		t.Fatalf("%s: dist=%d is shorter than bfs dist=%d", algorithm.Name, result.Dist, optimal)
+	}
+	if !algorithm.AnyAngle && !algorithm.Approximate && result.Dist != optimal {
+		t.Fatalf("%s: dist=%d, bfs dist=%d", algorithm.Name, result.Dist, optimal)
+	}
+}
+
+func TestSolversOnRandomBoards(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		rows, cols := 1+rnd.Intn(25), 1+rnd.Intn(25)
+		density := rnd.Float64() * 0.45
+		seed := rnd.Int63()
+
+		board, err := maze.GenerateRandom(rows, cols, density, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start, _ := testboards.RandomFreeCell(rnd, board)
+		var targets [][2]int
+		if rnd.Intn(4) == 0 {
+			targets = algorithms.GetBoundaryCells(board, start[0], start[1])
+		} else {
+			for n := 1 + rnd.Intn(3); len(targets) < n; {
+				target, _ := testboards.RandomFreeCell(rnd, board)
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+
+		for _, algorithm := range algorithms.All() {
+			name := fmt.Sprintf("%s/board=%d/%dx%d/density=%.2f/seed=%d", algorithm.Name, i, rows, cols, density, seed)
+			t.Run(name, func(t *testing.T) {
+				result := algorithm.Solve(board, start[0], start[1], targets)
+				checkResult(t, algorithm, board, start, targets, result)
+			})
+		}
+	}
+}
+
+// TestWeightedSolvers проверяет, что взвешенный решатель находит путь не длиннее кратчайшего, умноженного на вес
+func TestWeightedSolvers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, algorithm := range algorithms.All() {
+		if !algorithm.Weighted {
+			continue
+		}
+
+		for _, weight := range []float64{1, 1.5, 2, 5} {
+			solve := algorithm.Configure(algorithms.Options{Weight: weight})
+			for i := 0; i < 50; i++ {
+				board, err := maze.GenerateRandom(5+rnd.Intn(40), 5+rnd.Intn(40), rnd.Float64()*0.4, rnd.Int63())
+				if err != nil {
+					t.Fatal(err)
+				}
+				start, ok := testboards.RandomFreeCell(rnd, board)
+				target, _ := testboards.RandomFreeCell(rnd, board)
+				if !ok {
+					continue
+				}
+				targets := [][2]int{target}
+
+				// Проверка кратчайшего пути в checkResult не относится к весу больше 1
+				approximate := algorithm
+				approximate.Approximate = weight > 1
+				result := solve(board, start[0], start[1], targets)
+				checkResult(t, approximate, board, start, targets, result)
+				if optimal := bfs(board, start, targets); optimal != algorithms.PathNotFound && float64(result.Dist) > weight*float64(optimal) {
+					t.Fatalf("%s: weight %g, dist=%d, bfs dist=%d", algorithm.Name, weight, result.Dist, optimal)
+				}
+			}
+		}
+	}
+}
+
+// TestSolversWithHeuristics проверяет, что с любой эвристикой, допустимой для модели перемещения, алгоритм находит
+// корректный путь, а точный алгоритм — кратчайший
+func TestSolversWithHeuristics(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, algorithm := range algorithms.All() {
+		if !algorithm.Heuristics {
+			continue
+		}
+
+		for _, h := range heuristics.All() {
+			if heuristics.Check(h, algorithm.Movement) != nil {
+				continue
+			}
+
+			solve := algorithm.Configure(algorithms.Options{Heuristic: h})
+			t.Run(algorithm.Name+"/"+h.Name(), func(t *testing.T) {
+				for i := 0; i < 50; i++ {
+					board, err := maze.GenerateRandom(5+rnd.Intn(40), 5+rnd.Intn(40), rnd.Float64()*0.4, rnd.Int63())
+					if err != nil {
+						t.Fatal(err)
+					}
+					start, ok := testboards.RandomFreeCell(rnd, board)
+					target, _ := testboards.RandomFreeCell(rnd, board)
+					if !ok {
+						continue
+					}
+					targets := [][2]int{target}
+
+					checkResult(t, algorithm, board, start, targets, solve(board, start[0], start[1], targets))
+				}
+			})
+		}
+	}
+}
+
+// TestIndexUpdate проверяет, что обновленный индекс ищет пути так же, как индекс, построенный заново
+func TestIndexUpdate(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, algorithm := range algorithms.All() {
+		if algorithm.Preprocess == nil {
+			continue
+		}
+
+		for i := 0; i < 30; i++ {
+			board, err := maze.GenerateRandom(10+rnd.Intn(40), 10+rnd.Intn(40), 0.3, rnd.Int63())
+			if err != nil {
+				t.Fatal(err)
+			}
+			index := algorithm.Preprocess(board)
+
+			for step := 0; step < 5; step++ {
+				// Меняем несколько клеток, в том числе на границах кластеров и по краям лабиринта
+				for n := 1 + rnd.Intn(10); n > 0; n-- {
+					x, y := rnd.Intn(len(board)), rnd.Intn(len(board[0]))
+					board[x][y] = !board[x][y]
+				}
+				index = index.Update(board)
+				rebuilt := algorithm.Preprocess(board)
+
+				start, ok := testboards.RandomFreeCell(rnd, board)
+				target, _ := testboards.RandomFreeCell(rnd, board)
+				if !ok || start == target {
+					continue
+				}
+				targets := [][2]int{target}
+
+				name := fmt.Sprintf("%s/board=%d/step=%d", algorithm.Name, i, step)
+				result := index.Solve(start[0], start[1], targets)
+				checkResult(t, algorithm, board, start, targets, result)
+				if want := rebuilt.Solve(start[0], start[1], targets); result.Dist != want.Dist {
+					t.Fatalf("%s: updated index dist=%d, rebuilt index dist=%d", name, result.Dist, want.Dist)
+				}
+			}
+		}
+	}
+}
+
+func TestSolversOnGeneratedLabyrinths(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		board, err := maze.Generate(31, 41, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start := [2]int{1, 0}
+		targets := [][2]int{{29, 40}}
+
+		for _, algorithm := range algorithms.All() {
+			t.Run(fmt.Sprintf("%s/seed=%d", algorithm.Name, seed), func(t *testing.T) {
+				result := algorithm.Solve(board, start[0], start[1], targets)
+				checkResult(t, algorithm, board, start, targets, result)
+			})
+		}
+	}
+}
+
+func TestSolversOnShippedLabyrinths(t *testing.T) {
+	fixtures := []struct {
+		file    string
+		start   [2]int
+		targets [][2]int // nil означает все свободные клетки на границе
+		dist    int
+	}{
+		{file: "labyrinth_matrix_41x41.txt", start: [2]int{1, 0}, dist: 290},
+		{file: "labyrinth_matrix_41x41.txt", start: [2]int{1, 0}, targets: [][2]int{{39, 40}}, dist: 290},
+		{file: "labyrinth_matrix_41x41_many_targets.txt", start: [2]int{1, 0}, dist: 282},
+		{file: "labyrinth_matrix_41x41_many_targets.txt", start: [2]int{1, 0}, targets: [][2]int{{39, 40}}, dist: 290},
+		{file: "labyrinth_matrix_41x41_default.txt", start: [2]int{1, 0}, dist: 290},
+		{file: "labyrinth_matrix_41x41_many_targets_default.txt", start: [2]int{1, 0}, dist: 282},
+	}
+
+	for _, fixture := range fixtures {
+		board, err := maze.ParseMaze("../maze/" + fixture.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		targets := fixture.targets
+		if targets == nil {
+			targets = algorithms.GetBoundaryCells(board, fixture.start[0], fixture.start[1])
+		}
+
+		if dist := bfs(board, fixture.start, targets); dist != fixture.dist {
+			t.Fatalf("%s: bfs dist=%d, fixture dist=%d", fixture.file, dist, fixture.dist)
+		}
+
+		for _, algorithm := range algorithms.All() {
+			t.Run(fmt.Sprintf("%s/%s/targets=%d", algorithm.Name, fixture.file, len(targets)), func(t *testing.T) {
+				result := algorithm.Solve(board, fixture.start[0], fixture.start[1], targets)
+				checkResult(t, algorithm, board, fixture.start, targets, result)
+				if !algorithm.AnyAngle && !algorithm.Approximate && result.Dist != fixture.dist {
+					t.Fatalf("dist=%d, want %d", result.Dist, fixture.dist)
+				}
+			})
+		}
+	}
+}
+
+// TestAnyAngleSolversOnOpenBoards проверяет длину пути алгоритмов с произвольными углами на почти пустых досках.
+// Клетки отрезка с прямой видимостью образуют путь по сторонам той же длины, поэтому длина пути должна совпадать
+// с кратчайшей. На открытой доске узел часто находится сначала через худшего родителя, и без обновления узлов
+// в открытом списке путь получается длиннее
+func TestAnyAngleSolversOnOpenBoards(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		size := 5 + rnd.Intn(30)
+		seed := rnd.Int63()
+		board, err := maze.GenerateRandom(size, size, 0.1, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start, _ := testboards.RandomFreeCell(rnd, board)
+		target, _ := testboards.RandomFreeCell(rnd, board)
+		targets := [][2]int{target}
+		optimal := bfs(board, start, targets)
+
+		for _, algorithm := range algorithms.All() {
+			if !algorithm.AnyAngle {
+				continue
+			}
+			result := algorithm.Solve(board, start[0], start[1], targets)
+			checkResult(t, algorithm, board, start, targets, result)
+			if result.Dist != optimal {
+				t.Fatalf("%s: board %d (%dx%d, seed %d) from %v to %v: dist=%d, bfs dist=%d", algorithm.Name, i, size, size, seed, start, target, result.Dist, optimal)
+			}
+		}
+	}
+}
+
+func TestLineOfSightReference(t *testing.T) {
+	board := [][]bool{
+		{false, false, false},
+		{false, true, false},
+		{false, false, false},
+	}
+
+	tests := []struct {
+		a, b [2]int
+		want bool
+	}{
+		{a: [2]int{0, 0}, b: [2]int{0, 2}, want: true},
+		{a: [2]int{0, 0}, b: [2]int{2, 2}, want: false},
+		{a: [2]int{2, 2}, b: [2]int{0, 0}, want: false},
+		{a: [2]int{0, 1}, b: [2]int{2, 1}, want: false},
+		{a: [2]int{2, 0}, b: [2]int{2, 2}, want: true},
+		{a: [2]int{0, 2}, b: [2]int{2, 0}, want: false},
+	}
+
+	for _, test := range tests {
+		if got := lineOfSight(board, test.a, test.b); got != test.want {
+			t.Errorf("lineOfSight(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package testboards содержит общие для тестов и бенчмарков алгоритмов функции выбора клеток и запуска бенчмарков
+package testboards
+
+import (
+	"math/rand"
+	"testing"
+
+	"algo/algorithms"
+	"algo/maze"
+)
+
+// LastFreeCell возвращает самую дальнюю от (0, 0) свободную клетку при обходе с конца доски
+func LastFreeCell(board [][]bool) [2]int {
+	for i := len(board) - 1; i >= 0; i-- {
+		for j := len(board[i]) - 1; j >= 0; j-- {
+			if !board[i][j] {
+				return [2]int{i, j}
+			}
+		}
+	}
+	return [2]int{0, 0}
+}
+
+// RandomFreeCell возвращает случайную свободную клетку или false, если таких нет
+func RandomFreeCell(rnd *rand.Rand, board [][]bool) ([2]int, bool) {
+	var free [][2]int
+	for i, row := range board {
+		for j, cell := range row {
+			if !cell {
+				free = append(free, [2]int{i, j})
+			}
+		}
+	}
+	if len(free) == 0 {
+		return [2]int{}, false
+	}
+	return free[rnd.Intn(len(free))], true
+}
+
+// Benchmark измеряет время поиска пути и сообщает количество раскрытых узлов
+func Benchmark(b *testing.B, solve algorithms.Solver, board [][]bool, startX, startY int, targets [][2]int) {
+	b.Helper()
+	b.ReportAllocs()
+
+	var result algorithms.Result
+	for i := 0; i < b.N; i++ {
+		result = solve(board, startX, startY, targets)
+	}
+
+	if result.Dist == algorithms.PathNotFound {
+		b.Fatal("path not found")
+	}
+	b.ReportMetric(float64(result.Expanded), "expanded/op")
+}
+
+// BenchmarkGenerated401x401 измеряет поиск пути от входа до выхода сгенерированного лабиринта 401x401
+func BenchmarkGenerated401x401(b *testing.B, solve algorithms.Solver) {
+	b.Helper()
+
+	board, err := maze.Generate(401, 401, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	Benchmark(b, solve, board, 1, 0, [][2]int{{399, 400}})
+}
+
+// BenchmarkRandom1000x1000 измеряет поиск пути через случайную доску 1000x1000 от (0, 0) до последней свободной клетки
+func BenchmarkRandom1000x1000(b *testing.B, solve algorithms.Solver) {
+	b.Helper()
+
+	board, err := maze.GenerateRandom(1000, 1000, 0.25, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	Benchmark(b, solve, board, 0, 0, [][2]int{LastFreeCell(board)})
+}
@@ -0,0 +1,259 @@
+package waypoints
+
+import (
+	"slices"
+
+	"algo/algorithms"
+)
+
+// MaxExact наибольшее количество промежуточных клеток, для которых порядок обхода ищется точно.
+// Динамическое программирование по подмножествам занимает O(2^n·n²) времени и O(2^n·n) памяти
+const MaxExact = 12
+
+// StatLegs ключ статистики в Result.Stats: количество участков, на которые разбит маршрут
+const StatLegs = "legs"
+
+// unreachable стоимость перехода между клетками, между которыми нет пути. Сумма нескольких таких стоимостей
+// не переполняет int
+const unreachable = 1 << 40
+
+// Route путь от старта через все промежуточные клетки до ближайшей из целей
+type Route struct {
+	algorithms.Result
+	Order []int // Номера промежуточных клеток в порядке обхода
+	Exact bool  // Порядок задан явно или найден точным перебором, а не эвристикой
+}
+
+// Solve строит маршрут от старта через промежуточные клетки via до ближайшей из целей. Если optimal ложно,
+// клетки обходятся в заданном порядке, иначе порядок выбирается так, чтобы маршрут был кратчайшим. Маршрут
+// разбивается на участки между соседними клетками, каждый участок ищет solver, поэтому свойства решателя,
+// например граница субоптимальности, переносятся на маршрут при выбранном порядке
+func Solve(board [][]bool, start [2]int, via [][2]int, targets [][2]int, optimal bool, solver algorithms.Solver) Route {
+	route := Route{Order: make([]int, len(via)), Exact: true}
+	for i := range route.Order {
+		route.Order[i] = i
+	}
+	if optimal && len(via) > 1 {
+		route.Order, route.Exact = BestOrder(board, start, via, targets)
+	}
+
+	route.Result = algorithms.Result{Path: []algorithms.Node{{X: start[0], Y: start[1]}}}
+	current, legs := start, 0
+	follow := func(leg algorithms.Result) bool {
+		route.Expanded += leg.Expanded
+		legs++
+		if leg.Dist == algorithms.PathNotFound || len(leg.Path) == 0 {
+			return false
+		}
+		for _, node := range leg.Path[1:] {
+			node.G += route.Dist
+			route.Path = append(route.Path, node)
+		}
+		route.Dist += leg.Dist
+		last := leg.Path[len(leg.Path)-1]
+		current = [2]int{last.X, last.Y}
+		return true
+	}
+
+	for _, i := range route.Order {
+		if current == via[i] {
+			continue
+		}
+		if !follow(solver(board, current[0], current[1], [][2]int{via[i]})) {
+			return notFound(route, legs)
+		}
+	}
+	if !slices.Contains(targets, current) && !follow(solver(board, current[0], current[1], targets)) {
+		return notFound(route, legs)
+	}
+
+	route.Stats = map[string]int{StatLegs: legs}
+	return route
+}
+
+func notFound(route Route, legs int) Route {
+	route.Result = algorithms.NotFound(route.Expanded)
+	route.Stats = map[string]int{StatLegs: legs}
+	return route
+}
+
+// BestOrder выбирает порядок обхода промежуточных клеток, при котором путь от старта через все клетки
+// до ближайшей из целей кратчайший. Расстояния между клетками считаются обходом в ширину из старта и каждой
+// промежуточной клетки. Для не более чем MaxExact клеток порядок ищется точно алгоритмом Хелда — Карпа,
+// для большего количества строится жадно (ближайшая непосещенная клетка) и улучшается перестановками 2-opt.
+// Второе значение сообщает, найден ли порядок точно
+func BestOrder(board [][]bool, start [2]int, via [][2]int, targets [][2]int) ([]int, bool) {
+	costs := newCostMatrix(board, start, via, targets)
+	if len(via) <= MaxExact {
+		return costs.heldKarp(), true
+	}
+	return costs.twoOpt(costs.nearestNeighbor()), false
+}
+
+// costMatrix стоимости переходов: fromStart[i] — от старта до i-й клетки, between[i][j] — от i-й до j-й,
+// toTarget[i] — от i-й клетки до ближайшей цели
+type costMatrix struct {
+	fromStart []int
+	between   [][]int
+	toTarget  []int
+}
+
+func newCostMatrix(board [][]bool, start [2]int, via [][2]int, targets [][2]int) *costMatrix {
+	n := len(via)
+	costs := &costMatrix{fromStart: make([]int, n), between: make([][]int, n), toTarget: make([]int, n)}
+
+	cols := len(board[0])
+	at := func(dist []int, cell [2]int) int {
+		if d := dist[cell[0]*cols+cell[1]]; d != -1 {
+			return d
+		}
+		return unreachable
+	}
+
+	fromStart := bfs(board, start)
+	for i, cell := range via {
+		costs.fromStart[i] = at(fromStart, cell)
+
+		dist := bfs(board, cell)
+		costs.between[i] = make([]int, n)
+		for j, other := range via {
+			costs.between[i][j] = at(dist, other)
+		}
+		costs.toTarget[i] = unreachable
+		for _, target := range targets {
+			costs.toTarget[i] = min(costs.toTarget[i], at(dist, target))
+		}
+	}
+
+	return costs
+}
+
+// cost возвращает длину маршрута при порядке обхода order
+func (costs *costMatrix) cost(order []int) int {
+	total := costs.fromStart[order[0]] + costs.toTarget[order[len(order)-1]]
+	for i := 1; i < len(order); i++ {
+		total += costs.between[order[i-1]][order[i]]
+	}
+	return total
+}
+
+// heldKarp находит кратчайший порядок динамическим программированием: best[mask][last] — длина кратчайшего
+// пути от старта через клетки из mask, заканчивающегося в клетке last
+func (costs *costMatrix) heldKarp() []int {
+	n := len(costs.fromStart)
+	full := 1<<n - 1
+
+	best := make([][]int, full+1)
+	parent := make([][]int, full+1)
+	for mask := range best {
+		best[mask] = make([]int, n)
+		parent[mask] = make([]int, n)
+		for last := range best[mask] {
+			best[mask][last] = -1
+		}
+	}
+	for i := 0; i < n; i++ {
+		best[1<<i][i] = costs.fromStart[i]
+		parent[1<<i][i] = -1
+	}
+
+	for mask := 1; mask <= full; mask++ {
+		for last := 0; last < n; last++ {
+			current := best[mask][last]
+			if current == -1 {
+				continue
+			}
+			for next := 0; next < n; next++ {
+				if mask&(1<<next) != 0 {
+					continue
+				}
+				extended := mask | 1<<next
+				if candidate := current + costs.between[last][next]; best[extended][next] == -1 || candidate < best[extended][next] {
+					best[extended][next] = candidate
+					parent[extended][next] = last
+				}
+			}
+		}
+	}
+
+	last := 0
+	for i := 1; i < n; i++ {
+		if best[full][i]+costs.toTarget[i] < best[full][last]+costs.toTarget[last] {
+			last = i
+		}
+	}
+
+	order := make([]int, 0, n)
+	for mask := full; last != -1; {
+		order = append(order, last)
+		mask, last = mask&^(1<<last), parent[mask][last]
+	}
+	slices.Reverse(order)
+	return order
+}
+
+// nearestNeighbor строит порядок, переходя каждый раз в ближайшую непосещенную клетку
+func (costs *costMatrix) nearestNeighbor() []int {
+	n := len(costs.fromStart)
+	visited := make([]bool, n)
+	order := make([]int, 0, n)
+
+	distance := costs.fromStart
+	for len(order) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !visited[i] && (next == -1 || distance[i] < distance[next]) {
+				next = i
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		distance = costs.between[next]
+	}
+
+	return order
+}
+
+// twoOpt улучшает порядок, разворачивая участки, пока разворот хотя бы одного участка укорачивает маршрут
+func (costs *costMatrix) twoOpt(order []int) []int {
+	best := costs.cost(order)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for j := i + 1; j < len(order); j++ {
+				slices.Reverse(order[i : j+1])
+				if cost := costs.cost(order); cost < best {
+					best, improved = cost, true
+				} else {
+					slices.Reverse(order[i : j+1])
+				}
+			}
+		}
+	}
+	return order
+}
+
+// bfs считает расстояния от клетки до всех клеток лабиринта, -1 — клетка недостижима
+func bfs(board [][]bool, from [2]int) []int {
+	cols := len(board[0])
+	dist := make([]int, len(board)*cols)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	dist[from[0]*cols+from[1]] = 0
+	queue := [][2]int{from}
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		for _, dir := range [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			x, y := current[0]+dir[0], current[1]+dir[1]
+			if !algorithms.IsValid(board, x, y) || dist[x*cols+y] != -1 {
+				continue
+			}
+			dist[x*cols+y] = dist[current[0]*cols+current[1]] + 1
+			queue = append(queue, [2]int{x, y})
+		}
+	}
+
+	return dist
+}
@@ -0,0 +1,177 @@
+package waypoints
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"algo/algorithms"
+	"algo/algorithms/dijkstra"
+	"algo/maze"
+)
+
+// bruteForce перебирает все порядки обхода и возвращает длину кратчайшего маршрута
+func bruteForce(costs *costMatrix) int {
+	order := make([]int, len(costs.fromStart))
+	for i := range order {
+		order[i] = i
+	}
+
+	best := -1
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(order) {
+			if cost := costs.cost(order); best == -1 || cost < best {
+				best = cost
+			}
+			return
+		}
+		for i := k; i < len(order); i++ {
+			order[k], order[i] = order[i], order[k]
+			permute(k + 1)
+			order[k], order[i] = order[i], order[k]
+		}
+	}
+	permute(0)
+	return best
+}
+
+// checkRoute проверяет, что маршрут идет по соседним свободным клеткам, проходит промежуточные клетки
+// в порядке Order и заканчивается в цели
+func checkRoute(t *testing.T, board [][]bool, start [2]int, via [][2]int, targets [][2]int, route Route) {
+	t.Helper()
+
+	path := route.Path
+	if route.Dist != len(path)-1 {
+		t.Fatalf("dist %d, %d nodes", route.Dist, len(path))
+	}
+	if path[0].X != start[0] || path[0].Y != start[1] || !slices.Contains(targets, [2]int{path[len(path)-1].X, path[len(path)-1].Y}) {
+		t.Fatalf("route goes from %v to %v", path[0], path[len(path)-1])
+	}
+
+	next := 0
+	for i, node := range path {
+		if !algorithms.IsValid(board, node.X, node.Y) {
+			t.Fatalf("node %d (%d, %d) is wall", i, node.X, node.Y)
+		}
+		if i > 0 && abs(node.X-path[i-1].X)+abs(node.Y-path[i-1].Y) != 1 {
+			t.Fatalf("nodes %d and %d are not adjacent", i-1, i)
+		}
+		for next < len(route.Order) && via[route.Order[next]] == [2]int{node.X, node.Y} {
+			next++
+		}
+	}
+	if next != len(route.Order) {
+		t.Fatalf("route visits %d of %d via cells in order %v", next, len(route.Order), route.Order)
+	}
+}
+
+func TestBestOrderMatchesBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		board, err := maze.GenerateRandom(4+rnd.Intn(6), 4+rnd.Intn(6), rnd.Float64()*0.3, rnd.Int63())
+		if err != nil {
+			t.Fatal(err)
+		}
+		free := func() [2]int {
+			for {
+				cell := [2]int{rnd.Intn(len(board)), rnd.Intn(len(board[0]))}
+				if algorithms.IsValid(board, cell[0], cell[1]) {
+					return cell
+				}
+			}
+		}
+		start, targets := free(), [][2]int{free(), free()}
+		via := make([][2]int, 1+rnd.Intn(6))
+		for j := range via {
+			via[j] = free()
+		}
+
+		costs := newCostMatrix(board, start, via, targets)
+		want := bruteForce(costs)
+		order, exact := BestOrder(board, start, via, targets)
+		if !exact {
+			t.Fatalf("board %d: order of %d cells is not exact", i, len(via))
+		}
+		if got := costs.cost(order); got != want {
+			t.Fatalf("board %d: order %v costs %d, want %d", i, order, got, want)
+		}
+
+		route := Solve(board, start, via, targets, true, dijkstra.Dijkstra)
+		if want >= unreachable {
+			if route.Dist != algorithms.PathNotFound {
+				t.Fatalf("board %d: found route of %d steps through unreachable cells", i, route.Dist)
+			}
+			continue
+		}
+		checkRoute(t, board, start, via, targets, route)
+		if route.Dist != want {
+			t.Fatalf("board %d: route dist %d, want %d", i, route.Dist, want)
+		}
+	}
+}
+
+func TestFixedOrder(t *testing.T) {
+	board := make([][]bool, 5)
+	for i := range board {
+		board[i] = make([]bool, 5)
+	}
+	start, targets := [2]int{0, 0}, [][2]int{{4, 4}}
+	via := [][2]int{{4, 0}, {0, 4}, {0, 0}}
+
+	route := Solve(board, start, via, targets, false, dijkstra.Dijkstra)
+	checkRoute(t, board, start, via, targets, route)
+	if !slices.Equal(route.Order, []int{0, 1, 2}) || route.Dist != 4+8+4+8 {
+		t.Errorf("order %v, dist %d, want [0 1 2] and 24", route.Order, route.Dist)
+	}
+
+	optimal := Solve(board, start, via, targets, true, dijkstra.Dijkstra)
+	checkRoute(t, board, start, via, targets, optimal)
+	if optimal.Dist != 16 {
+		t.Errorf("optimal order %v, dist %d, want 16", optimal.Order, optimal.Dist)
+	}
+}
+
+func TestHeuristicOrder(t *testing.T) {
+	board, err := maze.GenerateRandom(30, 30, 0.2, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := [2]int{0, 0}
+	board[0][0] = false
+	targets := [][2]int{{29, 29}}
+	board[29][29] = false
+
+	rnd := rand.New(rand.NewSource(2))
+	var via [][2]int
+	for len(via) < MaxExact+8 {
+		cell := [2]int{rnd.Intn(30), rnd.Intn(30)}
+		if algorithms.IsValid(board, cell[0], cell[1]) && !slices.Contains(via, cell) {
+			via = append(via, cell)
+		}
+	}
+
+	costs := newCostMatrix(board, start, via, targets)
+	order, exact := BestOrder(board, start, via, targets)
+	if exact {
+		t.Fatalf("order of %d cells reported as exact", len(via))
+	}
+	sorted := slices.Clone(order)
+	slices.Sort(sorted)
+	for i, v := range sorted {
+		if v != i {
+			t.Fatalf("order %v is not a permutation", order)
+		}
+	}
+	if got, greedy := costs.cost(order), costs.cost(costs.nearestNeighbor()); got > greedy {
+		t.Errorf("2-opt order costs %d, more than nearest neighbor %d", got, greedy)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
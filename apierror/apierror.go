@@ -0,0 +1,86 @@
+// Package apierror описывает ошибки, которые возвращаются клиенту, и формат их тела в ответе
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Коды ошибок, возвращаемые клиенту в поле error.code
+const (
+	CodeInvalidRequest         = "INVALID_REQUEST"
+	CodeInvalidMazeID          = "INVALID_MAZE_ID"
+	CodeInvalidAlgorithmID     = "INVALID_ALGORITHM_ID"
+	CodeInvalidCoords          = "INVALID_COORDS"
+	CodeInvalidHeuristicWeight = "INVALID_HEURISTIC_WEIGHT"
+	CodeInvalidHeuristic       = "INVALID_HEURISTIC"
+	CodeInvalidMovement        = "INVALID_MOVEMENT"
+	CodeStartOutOfBounds       = "START_OUT_OF_BOUNDS"
+	CodeEndOutOfBounds         = "END_OUT_OF_BOUNDS"
+	CodeCellOutOfBounds        = "CELL_OUT_OF_BOUNDS"
+	CodeViaOutOfBounds         = "VIA_OUT_OF_BOUNDS"
+	CodeStartIsWall            = "START_IS_WALL"
+	CodeEndIsWall              = "END_IS_WALL"
+	CodeViaIsWall              = "VIA_IS_WALL"
+	CodeEmptyQueries           = "EMPTY_QUERIES"
+	CodeTooManyQueries         = "TOO_MANY_QUERIES"
+	CodeEmptyCells             = "EMPTY_CELLS"
+	CodeMazeReadOnly           = "MAZE_READ_ONLY"
+	CodeMazeNotRestorable      = "MAZE_NOT_RESTORABLE"
+	CodeNotFound               = "NOT_FOUND"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodeInternal               = "INTERNAL"
+)
+
+// Codes перечисляет все коды ошибок, используется для проверки документации API
+var Codes = []string{
+	CodeInvalidRequest, CodeInvalidMazeID, CodeInvalidAlgorithmID, CodeInvalidCoords, CodeInvalidHeuristicWeight, CodeInvalidHeuristic, CodeInvalidMovement,
+	CodeStartOutOfBounds, CodeEndOutOfBounds, CodeCellOutOfBounds, CodeViaOutOfBounds, CodeStartIsWall, CodeEndIsWall, CodeViaIsWall,
+	CodeEmptyQueries, CodeTooManyQueries, CodeEmptyCells, CodeMazeReadOnly, CodeMazeNotRestorable, CodeNotFound, CodeMethodNotAllowed, CodeInternal,
+}
+
+type ErrorOutput struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Index     *int   `json:"index,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// APIError представляет собой ошибку, которую нужно вернуть клиенту с заданным HTTP-статусом и кодом
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Index   *int // Индекс элемента массива запроса, вызвавшего ошибку
+}
+
+func (e *APIError) Error() string {
+	if e.Index != nil {
+		return fmt.Sprintf("%s: %s (index %d)", e.Code, e.Message, *e.Index)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Body возвращает тело ошибки для ответа
+func (e *APIError) Body() ErrorBody {
+	return ErrorBody{Code: e.Code, Message: e.Message, Index: e.Index}
+}
+
+// NewError возвращает ошибку с заданным HTTP-статусом
+func NewError(status int, code string, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// NewInvalidError возвращает ошибку некорректного запроса
+func NewInvalidError(code string, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: code, Message: message}
+}
+
+// NewInvalidIndexError возвращает ошибку некорректного элемента массива запроса
+func NewInvalidIndexError(code string, index int, message string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: code, Message: message, Index: &index}
+}
@@ -0,0 +1,116 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Форматы отчета
+const (
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "md"
+)
+
+var columns = []string{
+	"workload", "algorithm", "queries", "found", "optimal",
+	"mean_us", "p50_us", "p90_us", "p99_us", "max_us",
+	"mean_expanded", "mean_dist", "mean_suboptimality", "max_suboptimality", "preprocess_us",
+}
+
+// Write записывает отчет в заданном формате
+func (report Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return report.WriteCSV(w)
+	case FormatJSON:
+		return report.WriteJSON(w)
+	case FormatMarkdown:
+		return report.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// WriteCSV записывает отчет в формате CSV, время указывается в микросекундах
+func (report Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return errors.Wrap(err, "failed to write csv header")
+	}
+	for _, stats := range report.Stats {
+		if err := writer.Write(stats.row()); err != nil {
+			return errors.Wrap(err, "failed to write csv row")
+		}
+	}
+	writer.Flush()
+	return errors.Wrap(writer.Error(), "failed to flush csv")
+}
+
+// WriteJSON записывает отчет в формате JSON, время указывается в наносекундах
+func (report Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(report), "failed to encode json report")
+}
+
+// WriteMarkdown записывает отчет в виде markdown-таблицы, время указывается в микросекундах
+func (report Report) WriteMarkdown(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Seed: %d, runs per query: %d\n\n", report.Seed, report.Runs); err != nil {
+		return errors.Wrap(err, "failed to write markdown report")
+	}
+
+	lines := [][]string{columns, make([]string, len(columns))}
+	for i := range columns {
+		lines[1][i] = "---"
+	}
+	for _, stats := range report.Stats {
+		lines = append(lines, stats.row())
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprint(w, "|"); err != nil {
+			return errors.Wrap(err, "failed to write markdown report")
+		}
+		for _, cell := range line {
+			if _, err := fmt.Fprintf(w, " %s |", cell); err != nil {
+				return errors.Wrap(err, "failed to write markdown report")
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return errors.Wrap(err, "failed to write markdown report")
+		}
+	}
+
+	return nil
+}
+
+func (stats Stats) row() []string {
+	return []string{
+		stats.Workload,
+		stats.Algorithm,
+		strconv.Itoa(stats.Queries),
+		strconv.Itoa(stats.Found),
+		strconv.Itoa(stats.Optimal),
+		micros(stats.Mean),
+		micros(stats.P50),
+		micros(stats.P90),
+		micros(stats.P99),
+		micros(stats.Max),
+		strconv.FormatFloat(stats.MeanExpanded, 'f', 1, 64),
+		strconv.FormatFloat(stats.MeanDist, 'f', 1, 64),
+		strconv.FormatFloat(stats.MeanSuboptimality, 'f', 4, 64),
+		strconv.FormatFloat(stats.MaxSuboptimality, 'f', 4, 64),
+		micros(stats.Preprocess),
+	}
+}
+
+func micros(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Microsecond), 'f', 1, 64)
+}
@@ -0,0 +1,98 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() Report {
+	return Report{
+		Seed: 42,
+		Runs: 3,
+		Stats: []Stats{
+			{
+				Workload: "labyrinth_41x41", Algorithm: "a-star", Queries: 10, Found: 9, Optimal: 9,
+				Mean: 1500 * time.Nanosecond, P50: time.Microsecond, P90: 2 * time.Microsecond, P99: 3 * time.Microsecond, Max: 4 * time.Microsecond,
+				MeanExpanded: 120.25, MeanDist: 40.5, MeanSuboptimality: 1, MaxSuboptimality: 1,
+			},
+			{
+				Workload: "labyrinth_41x41", Algorithm: "hpa-star", Queries: 10, Found: 9, Optimal: 7,
+				Mean: 2 * time.Microsecond, P50: 2 * time.Microsecond, P90: 2 * time.Microsecond, P99: 2 * time.Microsecond, Max: 2 * time.Microsecond,
+				MeanExpanded: 30, MeanDist: 41, MeanSuboptimality: 1.0123, MaxSuboptimality: 1.05, Preprocess: time.Millisecond,
+			},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		columns,
+		{"labyrinth_41x41", "a-star", "10", "9", "9", "1.5", "1.0", "2.0", "3.0", "4.0", "120.2", "40.5", "1.0000", "1.0000", "0.0"},
+		{"labyrinth_41x41", "hpa-star", "10", "9", "7", "2.0", "2.0", "2.0", "2.0", "2.0", "30.0", "41.0", "1.0123", "1.0500", "1000.0"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("csv = %q, want %q", records, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report, testReport()) {
+		t.Errorf("json report = %+v, want %+v", report, testReport())
+	}
+	if !strings.Contains(buf.String(), `"preprocess": 1000000`) {
+		t.Errorf("json must keep durations in nanoseconds:\n%s", buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected seed line, blank line, header, separator and two rows, got:\n%s", buf.String())
+	}
+	if lines[0] != "Seed: 42, runs per query: 3" || lines[1] != "" {
+		t.Errorf("unexpected preamble %q", lines[:2])
+	}
+	if want := "| " + strings.Join(columns, " | ") + " |"; lines[2] != want {
+		t.Errorf("header = %q, want %q", lines[2], want)
+	}
+	if want := strings.Repeat("| --- ", len(columns)) + "|"; lines[3] != want {
+		t.Errorf("separator = %q, want %q", lines[3], want)
+	}
+	if !strings.HasPrefix(lines[5], "| labyrinth_41x41 | hpa-star | 10 | 9 | 7 | 2.0 |") || !strings.HasSuffix(lines[5], "| 1.0123 | 1.0500 | 1000.0 |") {
+		t.Errorf("unexpected row %q", lines[5])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testReport().Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
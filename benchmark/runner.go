@@ -0,0 +1,140 @@
+package benchmark
+
+import (
+	"slices"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/dijkstra"
+)
+
+// Stats представляет собой агрегированные результаты одного алгоритма на одной нагрузке
+type Stats struct {
+	Workload  string `json:"workload"`
+	Algorithm string `json:"algorithm"`
+	Queries   int    `json:"queries"`
+	Found     int    `json:"found"`   // Количество задач, в которых путь найден
+	Optimal   int    `json:"optimal"` // Количество задач, в которых найденное расстояние совпало с эталонным
+
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+
+	MeanExpanded      float64 `json:"mean_expanded"`
+	MeanDist          float64 `json:"mean_dist"`
+	MeanSuboptimality float64 `json:"mean_suboptimality"` // Среднее отношение найденного расстояния к оптимальному
+	MaxSuboptimality  float64 `json:"max_suboptimality"`
+
+	Preprocess time.Duration `json:"preprocess"` // Время построения индекса, не входит во время задач
+}
+
+// Report представляет собой результаты запуска бенчмарка
+type Report struct {
+	Seed  int64   `json:"seed"`
+	Runs  int     `json:"runs"`
+	Stats []Stats `json:"stats"`
+}
+
+// Run запускает каждый алгоритм runs раз на каждой задаче каждой нагрузки.
+// Качество путей оценивается относительно расстояния, найденного алгоритмом Дейкстры
+func Run(workloads []Workload, selected []algorithms.Algorithm, runs int, seed int64) Report {
+	if runs <= 0 {
+		runs = 1
+	}
+
+	report := Report{Seed: seed, Runs: runs}
+	for _, workload := range workloads {
+		optimal := make([]int, len(workload.Pairs))
+		for i, pair := range workload.Pairs {
+			optimal[i] = dijkstra.Dijkstra(workload.Board, pair.Start[0], pair.Start[1], pair.Targets).Dist
+		}
+
+		for _, algorithm := range selected {
+			report.Stats = append(report.Stats, runAlgorithm(workload, algorithm, optimal, runs))
+		}
+	}
+
+	return report
+}
+
+func runAlgorithm(workload Workload, algorithm algorithms.Algorithm, optimal []int, runs int) Stats {
+	stats := Stats{Workload: workload.Name, Algorithm: algorithm.Name, Queries: len(workload.Pairs)}
+
+	var (
+		samples       = make([]time.Duration, 0, len(workload.Pairs)*runs)
+		total         time.Duration
+		totalExpanded int
+		totalDist     int
+		totalRatio    float64
+		ratios        int
+	)
+
+	// Индекс строится один раз на нагрузку, задачи решаются по нему, как в сервисе
+	solve := algorithm.Solve
+	if algorithm.Preprocess != nil {
+		startTime := time.Now()
+		index := algorithm.Preprocess(workload.Board)
+		stats.Preprocess = time.Since(startTime)
+		solve = func(_ [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+			return index.Solve(startX, startY, targets)
+		}
+	}
+
+	for i, pair := range workload.Pairs {
+		var result algorithms.Result
+		for run := 0; run < runs; run++ {
+			startTime := time.Now()
+			result = solve(workload.Board, pair.Start[0], pair.Start[1], pair.Targets)
+			elapsed := time.Since(startTime)
+
+			samples = append(samples, elapsed)
+			total += elapsed
+		}
+
+		totalExpanded += result.Expanded
+		if result.Dist == optimal[i] {
+			stats.Optimal++
+		}
+		if result.Dist == algorithms.PathNotFound {
+			continue
+		}
+
+		stats.Found++
+		totalDist += result.Dist
+		if optimal[i] > 0 {
+			ratio := float64(result.Dist) / float64(optimal[i])
+			totalRatio += ratio
+			ratios++
+			stats.MaxSuboptimality = max(stats.MaxSuboptimality, ratio)
+		}
+	}
+
+	if len(samples) > 0 {
+		slices.Sort(samples)
+		stats.Mean = total / time.Duration(len(samples))
+		stats.P50 = percentile(samples, 0.5)
+		stats.P90 = percentile(samples, 0.9)
+		stats.P99 = percentile(samples, 0.99)
+		stats.Max = samples[len(samples)-1]
+	}
+	if stats.Queries > 0 {
+		stats.MeanExpanded = float64(totalExpanded) / float64(stats.Queries)
+	}
+	if stats.Found > 0 {
+		stats.MeanDist = float64(totalDist) / float64(stats.Found)
+	}
+	if ratios > 0 {
+		stats.MeanSuboptimality = totalRatio / float64(ratios)
+	}
+
+	return stats
+}
+
+// percentile возвращает перцентиль p отсортированной выборки методом ближайшего ранга
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
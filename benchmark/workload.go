@@ -0,0 +1,166 @@
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"algo/algorithms"
+	"algo/maze"
+	"github.com/pkg/errors"
+)
+
+// maxAttemptsPerPair ограничивает число попыток построить задачу, например, на доске без свободных клеток на границе
+const maxAttemptsPerPair = 100
+
+// Pair представляет собой одну задачу поиска пути: стартовую клетку и набор целевых клеток
+type Pair struct {
+	Start   [2]int   `json:"start"`
+	Targets [][2]int `json:"targets"`
+}
+
+// Workload представляет собой лабиринт и набор задач на нем
+type Workload struct {
+	Name  string
+	Board [][]bool
+	Pairs []Pair
+}
+
+// MazeSpec описывает лабиринт корпуса: либо файл, либо параметры генерации
+//
+// Строковое представление: путь к файлу ("maze/labyrinth_matrix_41x41.txt", "file.json:json")
+// или генератор ("labyrinth:201x201", "random:500x500:0.3")
+type MazeSpec struct {
+	Path    string
+	Format  maze.Format
+	Kind    string // labyrinth или random, если лабиринт генерируется
+	Rows    int
+	Cols    int
+	Density float64
+}
+
+// ParseMazeSpec разбирает строковое представление MazeSpec
+func ParseMazeSpec(value string) (MazeSpec, error) {
+	parts := strings.Split(value, ":")
+	switch parts[0] {
+	case "labyrinth", "random":
+		spec := MazeSpec{Kind: parts[0], Density: 0.3}
+		if len(parts) < 2 || len(parts) > 3 || (parts[0] == "labyrinth" && len(parts) != 2) {
+			return MazeSpec{}, fmt.Errorf("invalid generated maze spec %q", value)
+		}
+		if _, err := fmt.Sscanf(parts[1], "%dx%d", &spec.Rows, &spec.Cols); err != nil {
+			return MazeSpec{}, fmt.Errorf("invalid maze size in spec %q", value)
+		}
+		if len(parts) == 3 {
+			density, err := strconv.ParseFloat(parts[2], 64)
+			if err != nil {
+				return MazeSpec{}, fmt.Errorf("invalid density in spec %q", value)
+			}
+			spec.Density = density
+		}
+		return spec, nil
+	default:
+		spec := MazeSpec{Path: parts[0], Format: maze.FormatText}
+		if len(parts) == 2 {
+			format, err := maze.ParseFormat(parts[1])
+			if err != nil {
+				return MazeSpec{}, err
+			}
+			spec.Format = format
+		} else if len(parts) > 2 {
+			return MazeSpec{}, fmt.Errorf("invalid maze file spec %q", value)
+		}
+		return spec, nil
+	}
+}
+
+// Name возвращает имя лабиринта для отчетов
+func (spec MazeSpec) Name() string {
+	switch spec.Kind {
+	case "labyrinth":
+		return fmt.Sprintf("labyrinth_%dx%d", spec.Rows, spec.Cols)
+	case "random":
+		return fmt.Sprintf("random_%dx%d_%g", spec.Rows, spec.Cols, spec.Density)
+	default:
+		return strings.TrimSuffix(filepath.Base(spec.Path), filepath.Ext(spec.Path))
+	}
+}
+
+// Load читает или генерирует лабиринт, генерация детерминирована по seed
+func (spec MazeSpec) Load(seed int64) ([][]bool, error) {
+	switch spec.Kind {
+	case "labyrinth":
+		return maze.Generate(spec.Rows, spec.Cols, seed)
+	case "random":
+		return maze.GenerateRandom(spec.Rows, spec.Cols, spec.Density, seed)
+	default:
+		return maze.LoadMaze(spec.Path, spec.Format)
+	}
+}
+
+// NewWorkload строит набор из pairs случайных задач на лабиринте.
+// Если targets = 0, целями задачи являются все свободные клетки на границе, иначе — targets случайных свободных клеток.
+// При одинаковом seed набор задач всегда одинаков
+func NewWorkload(name string, board [][]bool, pairs, targets int, seed int64) (Workload, error) {
+	var free [][2]int
+	for i, row := range board {
+		for j := range row {
+			if algorithms.IsValid(board, i, j) {
+				free = append(free, [2]int{i, j})
+			}
+		}
+	}
+	if len(free) < 2 {
+		return Workload{}, fmt.Errorf("maze %s has less than two free cells", name)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	workload := Workload{Name: name, Board: board, Pairs: make([]Pair, 0, pairs)}
+	for attempt := 0; len(workload.Pairs) < pairs; attempt++ {
+		if attempt >= maxAttemptsPerPair*pairs {
+			return Workload{}, fmt.Errorf("failed to build %d queries for maze %s", pairs, name)
+		}
+
+		start := free[rnd.Intn(len(free))]
+
+		var pairTargets [][2]int
+		if targets == 0 {
+			pairTargets = algorithms.GetBoundaryCells(board, start[0], start[1])
+		} else {
+			for len(pairTargets) < targets {
+				if target := free[rnd.Intn(len(free))]; target != start {
+					pairTargets = append(pairTargets, target)
+				}
+			}
+		}
+		if len(pairTargets) == 0 {
+			continue
+		}
+
+		workload.Pairs = append(workload.Pairs, Pair{Start: start, Targets: pairTargets})
+	}
+
+	return workload, nil
+}
+
+// LoadCorpus строит нагрузку по каждому лабиринту корпуса
+func LoadCorpus(specs []MazeSpec, pairs, targets int, seed int64) ([]Workload, error) {
+	workloads := make([]Workload, 0, len(specs))
+	for i, spec := range specs {
+		mazeSeed := seed + int64(i)
+		board, err := spec.Load(mazeSeed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load maze %s", spec.Name())
+		}
+
+		workload, err := NewWorkload(spec.Name(), board, pairs, targets, mazeSeed)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, workload)
+	}
+
+	return workloads, nil
+}
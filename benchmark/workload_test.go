@@ -0,0 +1,110 @@
+package benchmark
+
+import (
+	"reflect"
+	"testing"
+
+	"algo/algorithms"
+	"algo/maze"
+)
+
+func TestNewWorkloadIsDeterministic(t *testing.T) {
+	board, err := maze.GenerateRandom(50, 50, 0.3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, targets := range []int{0, 3} {
+		first, err := NewWorkload("random", board, 20, targets, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := NewWorkload("random", board, 20, targets, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(first.Pairs, second.Pairs) {
+			t.Errorf("targets %d: same seed built different queries", targets)
+		}
+
+		other, err := NewWorkload("random", board, 20, targets, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reflect.DeepEqual(first.Pairs, other.Pairs) {
+			t.Errorf("targets %d: different seeds built the same queries", targets)
+		}
+
+		for i, pair := range first.Pairs {
+			if !algorithms.IsValid(board, pair.Start[0], pair.Start[1]) {
+				t.Errorf("targets %d, query %d: start %v is not a free cell", targets, i, pair.Start)
+			}
+			if targets > 0 && len(pair.Targets) != targets {
+				t.Errorf("targets %d, query %d: got %d targets", targets, i, len(pair.Targets))
+			}
+			for _, target := range pair.Targets {
+				if !algorithms.IsValid(board, target[0], target[1]) || target == pair.Start {
+					t.Errorf("targets %d, query %d: invalid target %v", targets, i, target)
+				}
+			}
+		}
+	}
+}
+
+func TestLoadCorpusIsDeterministic(t *testing.T) {
+	specs := []MazeSpec{
+		{Kind: "labyrinth", Rows: 21, Cols: 21},
+		{Kind: "random", Rows: 30, Cols: 30, Density: 0.2},
+	}
+
+	first, err := LoadCorpus(specs, 10, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadCorpus(specs, 10, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("same seed built different corpora")
+	}
+
+	if names := []string{first[0].Name, first[1].Name}; names[0] != "labyrinth_21x21" || names[1] != "random_30x30_0.2" {
+		t.Errorf("workload names = %v", names)
+	}
+}
+
+func TestNewWorkloadWithoutFreeCells(t *testing.T) {
+	board := [][]bool{{true, true}, {true, false}}
+	if _, err := NewWorkload("walls", board, 1, 1, 1); err == nil {
+		t.Error("expected error for a maze with one free cell")
+	}
+}
+
+func TestParseMazeSpec(t *testing.T) {
+	tests := []struct {
+		value string
+		spec  MazeSpec
+		name  string
+	}{
+		{value: "labyrinth:201x101", spec: MazeSpec{Kind: "labyrinth", Rows: 201, Cols: 101, Density: 0.3}, name: "labyrinth_201x101"},
+		{value: "random:500x500:0.25", spec: MazeSpec{Kind: "random", Rows: 500, Cols: 500, Density: 0.25}, name: "random_500x500_0.25"},
+		{value: "maze/labyrinth_matrix_41x41.txt", spec: MazeSpec{Path: "maze/labyrinth_matrix_41x41.txt", Format: maze.FormatText}, name: "labyrinth_matrix_41x41"},
+	}
+	for _, test := range tests {
+		spec, err := ParseMazeSpec(test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.value, err)
+			continue
+		}
+		if spec != test.spec || spec.Name() != test.name {
+			t.Errorf("%s: got %+v named %q, want %+v named %q", test.value, spec, spec.Name(), test.spec, test.name)
+		}
+	}
+
+	for _, value := range []string{"labyrinth:10", "labyrinth:10x10:0.3", "random:axb", "random:10x10:dense", "file.txt:txt:extra"} {
+		if _, err := ParseMazeSpec(value); err == nil {
+			t.Errorf("%s: expected error", value)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package buildinfo содержит сведения о сборке сервиса.
+// Commit и BuildTime задаются при сборке:
+//
+//	go build -ldflags "-X algo/buildinfo.Commit=$(git rev-parse HEAD) -X algo/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Если они не заданы, используются сведения о системе контроля версий, которые go build записывает в бинарный файл,
+// в этом случае вместо времени сборки возвращается время коммита
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+const unknown = "unknown"
+
+var (
+	// Commit хеш коммита, из которого собран сервис
+	Commit string
+	// BuildTime время сборки в формате RFC 3339
+	BuildTime string
+)
+
+// Info описывает сборку сервиса
+type Info struct {
+	Commit    string
+	BuildTime string
+	Modified  bool // Сборка сделана из рабочей копии с незакоммиченными изменениями
+	GoVersion string
+}
+
+// Get возвращает сведения о сборке
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+
+	return info
+}
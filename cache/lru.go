@@ -0,0 +1,125 @@
+// Package cache содержит LRU-кэш, безопасный для использования из нескольких горутин
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Stats статистика обращений к кэшу
+type Stats struct {
+	Hits     uint64
+	Misses   uint64
+	Size     int
+	Capacity int
+}
+
+// LRU кэш ограниченного размера, при переполнении вытесняется запись, к которой дольше всего не обращались.
+// Кэш нулевого размера ничего не хранит
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Начало списка — самая свежая запись
+	items    map[K]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: max(capacity, 0),
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get возвращает значение по ключу и отмечает запись как использованную
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.items[key]
+	if !found {
+		c.misses++
+		var zero V
+		return zero, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry[K, V]).value, true
+}
+
+// Add добавляет или заменяет значение по ключу
+func (c *LRU[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity == 0 {
+		return
+	}
+
+	if elem, found := c.items[key]; found {
+		elem.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	c.evict()
+}
+
+// RemoveFunc удаляет все записи, ключи которых удовлетворяют условию, и возвращает их количество
+func (c *LRU[K, V]) RemoveFunc(match func(key K) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, elem := range c.items {
+		if match(key) {
+			c.order.Remove(elem)
+			delete(c.items, key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// Resize меняет размер кэша, лишние записи вытесняются сразу
+func (c *LRU[K, V]) Resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = max(capacity, 0)
+	c.evict()
+}
+
+// Len возвращает количество записей в кэше
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats возвращает статистику обращений с момента создания кэша
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{Hits: c.hits, Misses: c.misses, Size: c.order.Len(), Capacity: c.capacity}
+}
+
+func (c *LRU[K, V]) evict() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+	}
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	// После обращения к a самой старой записью становится b
+	if value, found := c.Get("a"); !found || value != 1 {
+		t.Fatalf("Get(a) = %d, %t, want 1, true", value, found)
+	}
+	c.Add("c", 3)
+
+	if _, found := c.Get("b"); found {
+		t.Error("b must be evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if value, found := c.Get(key); !found || value != want {
+			t.Errorf("Get(%s) = %d, %t, want %d, true", key, value, found, want)
+		}
+	}
+
+	c.Add("a", 10)
+	if value, _ := c.Get("a"); value != 10 {
+		t.Errorf("Get(a) after replace = %d, want 10", value)
+	}
+
+	stats := c.Stats()
+	if stats != (Stats{Hits: 4, Misses: 1, Size: 2, Capacity: 2}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLRURemoveFuncAndResize(t *testing.T) {
+	c := NewLRU[int, int](10)
+	for i := range 10 {
+		c.Add(i, i)
+	}
+
+	if removed := c.RemoveFunc(func(key int) bool { return key%2 == 0 }); removed != 5 {
+		t.Errorf("RemoveFunc removed %d entries, want 5", removed)
+	}
+	if _, found := c.Get(4); found {
+		t.Error("4 must be removed")
+	}
+
+	// Остаются самые свежие записи 7 и 9
+	c.Resize(2)
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d after resize, want 2", c.Len())
+	}
+	for _, key := range []int{7, 9} {
+		if _, found := c.Get(key); !found {
+			t.Errorf("%d must stay after resize", key)
+		}
+	}
+
+	c.Resize(0)
+	c.Add(1, 1)
+	if c.Len() != 0 {
+		t.Errorf("cache of size 0 must stay empty, got %d entries", c.Len())
+	}
+}
+
+func TestLRUConcurrent(t *testing.T) {
+	c := NewLRU[int, int](16)
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := (worker + i) % 32
+				if _, found := c.Get(key); !found {
+					c.Add(key, key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := c.Stats(); stats.Hits+stats.Misses != 8000 || stats.Size > 16 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"algo/benchmark"
+)
+
+// mazeSpecs собирает значения повторяющегося флага --maze
+type mazeSpecs []benchmark.MazeSpec
+
+func (s *mazeSpecs) String() string {
+	names := make([]string, 0, len(*s))
+	for _, spec := range *s {
+		names = append(names, spec.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (s *mazeSpecs) Set(value string) error {
+	spec, err := benchmark.ParseMazeSpec(value)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, spec)
+	return nil
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	var specs mazeSpecs
+	fs.Var(&specs, "maze", "лабиринт корпуса, флаг можно повторять: путь[:формат], labyrinth:RxC или random:RxC[:плотность]")
+	algo := fs.String("algo", "all", "алгоритмы через запятую или all")
+	pairs := fs.Int("pairs", 50, "количество случайных задач на каждом лабиринте")
+	targets := fs.Int("targets", 0, "количество случайных целей в задаче, 0 — все свободные клетки на границе")
+	runs := fs.Int("runs", 5, "количество запусков алгоритма на каждой задаче")
+	seed := fs.Int64("seed", 1, "зерно генератора задач и лабиринтов")
+	report := fs.String("report", benchmark.FormatMarkdown, "формат отчета: md, csv или json")
+	out := fs.String("out", "", "файл для записи отчета, по умолчанию stdout")
+	fs.Parse(args)
+
+	if len(specs) == 0 {
+		return fmt.Errorf("at least one --maze is required")
+	}
+	if *pairs <= 0 || *runs <= 0 || *targets < 0 {
+		return fmt.Errorf("flags --pairs and --runs must be positive, --targets must not be negative")
+	}
+
+	selected, err := resolveAlgorithms(*algo)
+	if err != nil {
+		return err
+	}
+
+	workloads, err := benchmark.LoadCorpus(specs, *pairs, *targets, *seed)
+	if err != nil {
+		return err
+	}
+
+	result := benchmark.Run(workloads, selected, *runs, *seed)
+
+	if *out == "" {
+		return result.Write(os.Stdout, *report)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err = result.Write(file, *report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"algo/maze"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	var mf mazeFlags
+	mf.register(fs)
+	to := fs.String("to", string(maze.FormatCompact), "формат вывода (txt, compact, ascii, json)")
+	out := fs.String("out", "", "файл для записи, по умолчанию stdout")
+	fs.Parse(args)
+
+	board, err := mf.load()
+	if err != nil {
+		return err
+	}
+
+	outFormat, err := maze.ParseFormat(*to)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return maze.WriteMaze(os.Stdout, board, outFormat)
+	}
+	return maze.SaveMaze(*out, board, outFormat)
+}
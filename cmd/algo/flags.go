@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"algo/algorithms"
+	"algo/maze"
+)
+
+// mazeFlags описывает общие флаги для команд, читающих лабиринт из файла
+type mazeFlags struct {
+	path   string
+	format string
+}
+
+func (f *mazeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.path, "maze", "", "путь к файлу лабиринта")
+	fs.StringVar(&f.format, "format", string(maze.FormatText), "формат файла лабиринта (txt, compact, ascii, json)")
+}
+
+func (f *mazeFlags) load() ([][]bool, error) {
+	if f.path == "" {
+		return nil, fmt.Errorf("flag --maze is required")
+	}
+
+	format, err := maze.ParseFormat(f.format)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := maze.LoadMaze(f.path, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(board) == 0 || len(board[0]) == 0 {
+		return nil, fmt.Errorf("maze %s is empty", f.path)
+	}
+
+	return board, nil
+}
+
+// parseCell разбирает клетку в виде "строка,столбец"
+func parseCell(value string) ([2]int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ",")
+	if len(parts) != 2 {
+		return [2]int{}, fmt.Errorf("invalid cell %q, expected row,col", value)
+	}
+
+	var cell [2]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return [2]int{}, fmt.Errorf("invalid cell %q, expected row,col", value)
+		}
+		cell[i] = n
+	}
+
+	return cell, nil
+}
+
+// parseCells разбирает список клеток, разделенных точкой с запятой
+func parseCells(value string) ([][2]int, error) {
+	var cells [][2]int
+	for _, part := range strings.Split(value, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		cell, err := parseCell(part)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
+
+// resolveTargets возвращает заданные конечные клетки или все свободные клетки на границе
+func resolveTargets(board [][]bool, start [2]int, end string) ([][2]int, error) {
+	if !algorithms.IsValid(board, start[0], start[1]) {
+		return nil, fmt.Errorf("start cell %d,%d is a wall or out of bounds", start[0], start[1])
+	}
+
+	if end == "" {
+		return algorithms.GetBoundaryCells(board, start[0], start[1]), nil
+	}
+
+	targets, err := parseCells(end)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		if !algorithms.IsValid(board, target[0], target[1]) {
+			return nil, fmt.Errorf("end cell %d,%d is a wall or out of bounds", target[0], target[1])
+		}
+	}
+
+	return targets, nil
+}
+
+// resolveAlgorithms возвращает алгоритм по имени или идентификатору, "all" означает все алгоритмы
+func resolveAlgorithms(value string) ([]algorithms.Algorithm, error) {
+	if value == "all" {
+		return algorithms.All(), nil
+	}
+
+	var result []algorithms.Algorithm
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		algorithm, found := algorithms.GetByName(name)
+		if !found {
+			if id, err := strconv.Atoi(name); err == nil {
+				algorithm, found = algorithms.Get(id)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown algorithm %q, available: %s", name, algorithmNames())
+		}
+		result = append(result, algorithm)
+	}
+
+	return result, nil
+}
+
+func algorithmNames() string {
+	var names []string
+	for _, algorithm := range algorithms.All() {
+		names = append(names, algorithm.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"algo/maze"
+)
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	kind := fs.String("kind", "labyrinth", "тип доски: labyrinth (идеальный лабиринт) или random (случайные стены)")
+	rows := fs.Int("rows", 41, "количество строк")
+	cols := fs.Int("cols", 41, "количество столбцов")
+	density := fs.Float64("density", 0.3, "доля стен для --kind random")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "зерно генератора случайных чисел")
+	format := fs.String("format", string(maze.FormatText), "формат вывода (txt, compact, ascii, json)")
+	out := fs.String("out", "", "файл для записи, по умолчанию stdout")
+	fs.Parse(args)
+
+	outFormat, err := maze.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	var board [][]bool
+	switch *kind {
+	case "labyrinth":
+		board, err = maze.Generate(*rows, *cols, *seed)
+	case "random":
+		board, err = maze.GenerateRandom(*rows, *cols, *density, *seed)
+	default:
+		return fmt.Errorf("unknown kind %q", *kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return maze.WriteMaze(os.Stdout, board, outFormat)
+	}
+	return maze.SaveMaze(*out, board, outFormat)
+}
@@ -0,0 +1,73 @@
+// Команда algo позволяет решать, отображать, генерировать и конвертировать лабиринты
+// без запуска HTTP-сервера.
+//
+// Использование:
+//
+//	algo <команда> [флаги]
+//
+// Клетки задаются в виде "строка,столбец" (например, --start 1,0), несколько клеток
+// перечисляются через точку с запятой (например, --end "39,40;40,1").
+package main
+
+import (
+	"fmt"
+	"os"
+
+	_ "algo/algorithms/a_star"
+	_ "algo/algorithms/alt"
+	_ "algo/algorithms/ara_star"
+	_ "algo/algorithms/bidirectional_a_star"
+	_ "algo/algorithms/bidirectional_bfs"
+	_ "algo/algorithms/dijkstra"
+	_ "algo/algorithms/greedy_best_first"
+	_ "algo/algorithms/hpa_star"
+	_ "algo/algorithms/lazy_theta_star"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "solve", usage: "найти кратчайший путь в лабиринте", run: runSolve},
+	{name: "render", usage: "вывести лабиринт в терминал", run: runRender},
+	{name: "generate", usage: "сгенерировать лабиринт", run: runGenerate},
+	{name: "convert", usage: "конвертировать лабиринт в другой формат", run: runConvert},
+	{name: "bench", usage: "замерить время работы алгоритмов", run: runBench},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Использование: algo <команда> [флаги]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Команды:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Подробнее о флагах команды: algo <команда> -h")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "algo %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if os.Args[1] != "-h" && os.Args[1] != "--help" && os.Args[1] != "help" {
+		fmt.Fprintf(os.Stderr, "algo: unknown command %q\n\n", os.Args[1])
+	}
+	usage()
+	os.Exit(2)
+}
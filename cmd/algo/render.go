@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+
+	"algo/algorithms"
+)
+
+func runRender(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	var mf mazeFlags
+	mf.register(fs)
+	start := fs.String("start", "", "стартовая клетка, выделяется синим")
+	end := fs.String("end", "", "конечные клетки через ';', выделяются зеленым")
+	fs.Parse(args)
+
+	board, err := mf.load()
+	if err != nil {
+		return err
+	}
+
+	startCell := [2]int{-1, -1}
+	if *start != "" {
+		if startCell, err = parseCell(*start); err != nil {
+			return err
+		}
+	}
+
+	targets, err := parseCells(*end)
+	if err != nil {
+		return err
+	}
+
+	algorithms.PrintBoard(board, nil, targets, startCell[0], startCell[1])
+	return nil
+}
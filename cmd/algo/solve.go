@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"algo/algorithms"
+)
+
+type solveOutput struct {
+	Algorithm string        `json:"algorithm"`
+	Dist      int           `json:"dist"`
+	Expanded  int           `json:"expanded"`
+	Time      time.Duration `json:"time"`
+	Path      [][2]int      `json:"path"`
+}
+
+func runSolve(args []string) error {
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	var mf mazeFlags
+	mf.register(fs)
+	algo := fs.String("algo", "a-star", "алгоритм: "+algorithmNames()+" или all")
+	start := fs.String("start", "", "стартовая клетка в виде строка,столбец")
+	end := fs.String("end", "", "конечные клетки через ';', по умолчанию все свободные клетки на границе")
+	quiet := fs.Bool("quiet", false, "не выводить лабиринт с путем")
+	asJSON := fs.Bool("json", false, "вывести результат в формате JSON")
+	fs.Parse(args)
+
+	if *start == "" {
+		return fmt.Errorf("flag --start is required")
+	}
+
+	board, err := mf.load()
+	if err != nil {
+		return err
+	}
+
+	startCell, err := parseCell(*start)
+	if err != nil {
+		return err
+	}
+
+	targets, err := resolveTargets(board, startCell, *end)
+	if err != nil {
+		return err
+	}
+
+	selected, err := resolveAlgorithms(*algo)
+	if err != nil {
+		return err
+	}
+
+	var outputs []solveOutput
+	for _, algorithm := range selected {
+		startTime := time.Now()
+		result := algorithm.Solve(board, startCell[0], startCell[1], targets)
+		elapsed := time.Since(startTime)
+
+		output := solveOutput{Algorithm: algorithm.Name, Dist: result.Dist, Expanded: result.Expanded, Time: elapsed}
+		for _, node := range result.Path {
+			output.Path = append(output.Path, [2]int{node.X, node.Y})
+		}
+		outputs = append(outputs, output)
+
+		if *asJSON {
+			continue
+		}
+
+		fmt.Printf("%s (%s)\n", algorithm.Title, algorithm.Name)
+		if result.Dist == algorithms.PathNotFound {
+			fmt.Println("Путь до выхода не найден")
+			continue
+		}
+		fmt.Printf("Кратчайшее расстояние: %d\n", result.Dist)
+		fmt.Printf("Раскрыто узлов: %d\n", result.Expanded)
+		fmt.Printf("Время работы: %v\n", elapsed)
+		if !*quiet {
+			algorithms.PrintBoard(board, result.Path, targets, startCell[0], startCell[1])
+		}
+		fmt.Println()
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(outputs)
+	}
+
+	return nil
+}
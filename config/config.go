@@ -1,51 +1,306 @@
 package config
 
 import (
+	stderrors "errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"reflect"
+	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
 type LoggerKey string
 
-const LoggerContextKey LoggerKey = "logger"
+const (
+	LoggerContextKey    LoggerKey = "logger"
+	RequestIDContextKey LoggerKey = "request_id"
+)
 
+// Config конфигурация сервиса. Поля с тегом env можно переопределить переменной окружения,
+// каталог лабиринтов задается только в файле
 type Config struct {
 	Main MainConfig `yaml:"main"`
 	App  AppConfig  `yaml:"app"`
 }
 
+// MainConfig параметры HTTP-сервера, применяются только при запуске
 type MainConfig struct {
-	Port              string        `yaml:"port"`
-	ReadTimeout       time.Duration `yaml:"read_timeout"`
-	WriteTimeout      time.Duration `yaml:"write_timeout"`
-	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
-	IdleTimeout       time.Duration `yaml:"idle_timeout"`
-	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	Port              string        `yaml:"port" env:"MAIN_PORT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"MAIN_READ_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"MAIN_WRITE_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"MAIN_READ_HEADER_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"MAIN_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"MAIN_SHUTDOWN_TIMEOUT"`
 }
 
+// AppConfig параметры обработки запросов, перечитываются по SIGHUP без перезапуска сервера.
+// Список лабиринтов задается только в файле
 type AppConfig struct {
-	MazeCount      int `yaml:"maze_count"`
-	AlgorithmCount int `yaml:"algorithm_count"`
+	Mazes            []MazeConfig `yaml:"mazes"`
+	BatchParallelism int          `yaml:"batch_parallelism" env:"APP_BATCH_PARALLELISM"` // 0 — по числу процессоров
+	BatchMaxQueries  int          `yaml:"batch_max_queries" env:"APP_BATCH_MAX_QUERIES"` // 0 — без ограничения
+	CacheSize        int          `yaml:"cache_size" env:"APP_CACHE_SIZE"`               // 0 — кэш результатов отключен
+	// MazeCount устаревший параметр, количество лабиринтов определяется каталогом Mazes. Читается, чтобы старые
+	// конфигурации загружались, но не применяется
+	MazeCount *int `yaml:"maze_count"`
+}
+
+// MazeConfig описывает лабиринт из каталога. В запросах на лабиринт можно сослаться по ID или по Name
+type MazeConfig struct {
+	ID          int             `yaml:"id"`
+	Name        string          `yaml:"name"`
+	Path        string          `yaml:"path"`
+	Format      string          `yaml:"format"`    // Формат файлов Path и Original, по умолчанию txt
+	Original    string          `yaml:"original"`  // Исходная версия лабиринта для восстановления, без нее восстановление недоступно
+	ReadOnly    bool            `yaml:"read_only"` // Лабиринт нельзя изменить через API
+	Description string          `yaml:"description"`
+	Landmarks   LandmarksConfig `yaml:"landmarks"` // Ориентиры алгоритма ALT
+}
+
+// LandmarksConfig параметры выбора ориентиров ALT. Ориентиры выбираются при загрузке и после изменения лабиринта
+type LandmarksConfig struct {
+	Count    int    `yaml:"count"`    // Количество ориентиров, 0 — по умолчанию
+	Strategy string `yaml:"strategy"` // farthest или avoid, пустая строка — по умолчанию
+}
+
+// MaxLandmarks наибольшее количество ориентиров: каждый ориентир хранит расстояния до всех клеток лабиринта
+const MaxLandmarks = 64
+
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() Config {
+	return Config{
+		Main: MainConfig{
+			Port:              "8080",
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       30 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		App: AppConfig{
+			BatchMaxQueries: 1000,
+			CacheSize:       1000,
+		},
+	}
+}
+
+// Load читает конфигурацию: значения по умолчанию перекрываются файлом, а файл — переменными окружения.
+// Пустой path означает, что файл не используется. Неизвестные поля в файле считаются ошибкой
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, errors.Wrap(err, "invalid config")
+	}
+
+	return cfg, nil
 }
 
+// MustLoadConfig загружает конфигурацию и завершает процесс, если она некорректна
 func MustLoadConfig(path string, logger *slog.Logger) *Config {
-	cfg := &Config{}
+	cfg, err := Load(path)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to load config: %v", err))
+		os.Exit(1)
+	}
+	for _, warning := range cfg.Warnings() {
+		logger.Warn(warning)
+	}
+
+	return &cfg
+}
+
+// Warnings возвращает предупреждения об устаревших параметрах, которые читаются, но не применяются
+func (cfg Config) Warnings() []string {
+	var warnings []string
+	if cfg.App.MazeCount != nil {
+		warnings = append(warnings, "app.maze_count is deprecated and ignored, the number of mazes is defined by app.mazes")
+	}
+
+	return warnings
+}
 
+func loadFile(path string, cfg *Config) error {
 	file, err := os.Open(path)
 	if err != nil {
-		logger.Error(fmt.Sprintf("failed to open config file: %v", err))
-		return &Config{}
+		return errors.Wrap(err, "failed to open config file")
 	}
 	defer file.Close()
 
-	if err = yaml.NewDecoder(file).Decode(cfg); err != nil {
-		logger.Error(fmt.Sprintf("failed to decode config file: %v", err))
-		return &Config{}
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return errors.Wrapf(err, "failed to decode config file %s", path)
+	}
+
+	return nil
+}
+
+// applyEnv заполняет поля с тегом env из переменных окружения
+func applyEnv(value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field, fieldType := value.Field(i), value.Type().Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := fieldType.Tag.Get("env")
+		raw, found := os.LookupEnv(name)
+		if name == "" || !found {
+			continue
+		}
+
+		switch {
+		case field.Type() == reflect.TypeFor[time.Duration]():
+			duration, err := time.ParseDuration(raw)
+			if err != nil {
+				return fmt.Errorf("%s: invalid duration %q, expected a value like 10s or 1m30s", name, raw)
+			}
+			field.SetInt(int64(duration))
+		case field.Kind() == reflect.Int:
+			number, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: invalid integer %q", name, raw)
+			}
+			field.SetInt(int64(number))
+		case field.Kind() == reflect.String:
+			field.SetString(raw)
+		default:
+			return fmt.Errorf("%s: unsupported field type %s", name, field.Type())
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет всю конфигурацию и возвращает все найденные ошибки сразу
+func (cfg Config) Validate() error {
+	return stderrors.Join(cfg.Main.Validate(), cfg.App.Validate())
+}
+
+// Validate проверяет параметры HTTP-сервера
+func (cfg MainConfig) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(cfg.Port); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("main.port must be a number from 1 to 65535, got %q", cfg.Port))
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{name: "main.read_timeout", value: cfg.ReadTimeout},
+		{name: "main.write_timeout", value: cfg.WriteTimeout},
+		{name: "main.read_header_timeout", value: cfg.ReadHeaderTimeout},
+		{name: "main.idle_timeout", value: cfg.IdleTimeout},
+		{name: "main.shutdown_timeout", value: cfg.ShutdownTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", timeout.name, timeout.value))
+		}
+	}
+
+	if cfg.ReadHeaderTimeout > cfg.ReadTimeout {
+		errs = append(errs, fmt.Errorf("main.read_header_timeout (%s) must not exceed main.read_timeout (%s)", cfg.ReadHeaderTimeout, cfg.ReadTimeout))
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// Validate проверяет параметры приложения
+func (cfg AppConfig) Validate() error {
+	var errs []error
+
+	if len(cfg.Mazes) == 0 {
+		errs = append(errs, errors.New("app.mazes must not be empty"))
+	}
+
+	ids := make(map[int]bool, len(cfg.Mazes))
+	names := make(map[string]bool, len(cfg.Mazes))
+	for i, maze := range cfg.Mazes {
+		for _, err := range maze.validate() {
+			errs = append(errs, fmt.Errorf("app.mazes[%d]: %w", i, err))
+		}
+
+		if ids[maze.ID] {
+			errs = append(errs, fmt.Errorf("app.mazes[%d]: duplicate id %d", i, maze.ID))
+		}
+		ids[maze.ID] = true
+
+		if names[maze.Name] {
+			errs = append(errs, fmt.Errorf("app.mazes[%d]: duplicate name %q", i, maze.Name))
+		}
+		names[maze.Name] = true
+	}
+
+	if cfg.BatchParallelism < 0 {
+		errs = append(errs, fmt.Errorf("app.batch_parallelism must not be negative, got %d", cfg.BatchParallelism))
+	}
+	if cfg.BatchMaxQueries < 0 {
+		errs = append(errs, fmt.Errorf("app.batch_max_queries must not be negative, got %d", cfg.BatchMaxQueries))
+	}
+	if cfg.CacheSize < 0 {
+		errs = append(errs, fmt.Errorf("app.cache_size must not be negative, got %d", cfg.CacheSize))
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// mazeNameRe ограничивает имена лабиринтов символами, которые можно использовать в пути запроса без экранирования
+var mazeNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Validate проверяет описание лабиринта. Формат проверяется при построении каталога в пакете maze
+func (cfg MazeConfig) Validate() error {
+	return stderrors.Join(cfg.validate()...)
+}
+
+func (cfg MazeConfig) validate() []error {
+	var errs []error
+
+	if cfg.ID <= 0 {
+		errs = append(errs, fmt.Errorf("id must be positive, got %d", cfg.ID))
+	}
+	if cfg.Name == "" {
+		errs = append(errs, errors.New("name must not be empty"))
+	} else if _, err := strconv.Atoi(cfg.Name); err == nil {
+		errs = append(errs, fmt.Errorf("name %q must not be a number, numbers refer to ids", cfg.Name))
+	} else if !mazeNameRe.MatchString(cfg.Name) {
+		errs = append(errs, fmt.Errorf("name %q may contain only latin letters, digits, '-', '_' and '.'", cfg.Name))
+	}
+	if cfg.Path == "" {
+		errs = append(errs, errors.New("path must not be empty"))
+	}
+	if cfg.Original != "" && cfg.Original == cfg.Path {
+		errs = append(errs, fmt.Errorf("original must differ from path %q", cfg.Path))
+	}
+	if cfg.Landmarks.Count < 0 || cfg.Landmarks.Count > MaxLandmarks {
+		errs = append(errs, fmt.Errorf("landmarks.count must be from 0 to %d, got %d", MaxLandmarks, cfg.Landmarks.Count))
+	}
+	if strategy := cfg.Landmarks.Strategy; strategy != "" && strategy != "farthest" && strategy != "avoid" {
+		errs = append(errs, fmt.Errorf("landmarks.strategy must be farthest or avoid, got %q", strategy))
 	}
 
-	return cfg
+	return errs
 }
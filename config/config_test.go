@@ -0,0 +1,187 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testMazes минимальный корректный каталог лабиринтов
+const testMazes = "  mazes:\n    - id: 1\n      name: small\n      path: small.txt\n"
+
+func TestLoadShippedConfig(t *testing.T) {
+	cfg, err := Load("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Main.Port != "8080" || len(cfg.App.Mazes) != 2 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "app:\n"+testMazes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Default()
+	if cfg.Main != want.Main || cfg.App.BatchMaxQueries != want.App.BatchMaxQueries || cfg.App.BatchParallelism != 0 || cfg.App.CacheSize != want.App.CacheSize {
+		t.Errorf("got %+v, want defaults %+v", cfg, want)
+	}
+	if maze := cfg.App.Mazes[0]; maze != (MazeConfig{ID: 1, Name: "small", Path: "small.txt"}) {
+		t.Errorf("maze = %+v", maze)
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	t.Setenv("MAIN_PORT", "9090")
+	t.Setenv("MAIN_WRITE_TIMEOUT", "1m30s")
+	t.Setenv("APP_BATCH_PARALLELISM", "3")
+	t.Setenv("APP_BATCH_MAX_QUERIES", "0")
+	t.Setenv("APP_CACHE_SIZE", "0")
+
+	cfg, err := Load(writeConfig(t, "main:\n  port: 8080\napp:\n  batch_max_queries: 10\n"+testMazes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Main.Port != "9090" {
+		t.Errorf("port = %q, want 9090", cfg.Main.Port)
+	}
+	if cfg.Main.WriteTimeout != 90*time.Second {
+		t.Errorf("write_timeout = %s, want 1m30s", cfg.Main.WriteTimeout)
+	}
+	if cfg.App.BatchParallelism != 3 || cfg.App.BatchMaxQueries != 0 || cfg.App.CacheSize != 0 {
+		t.Errorf("app = %+v, want batch_parallelism 3, batch_max_queries 0 and cache_size 0", cfg.App)
+	}
+}
+
+func TestLoadDeprecatedMazeCount(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "app:\n  maze_count: 5\n"+testMazes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "app.maze_count is deprecated") {
+		t.Errorf("warnings = %q, want maze_count deprecation", warnings)
+	}
+	if len(cfg.App.Mazes) != 1 {
+		t.Errorf("mazes = %+v, maze_count must not change the catalog", cfg.App.Mazes)
+	}
+
+	cfg, err = Load(writeConfig(t, "app:\n"+testMazes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := cfg.Warnings(); len(warnings) != 0 {
+		t.Errorf("warnings = %q, want none", warnings)
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	_, err := Load("")
+	if err == nil || !strings.Contains(err.Error(), "app.mazes must not be empty") {
+		t.Fatalf("expected empty catalog error, got %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		errors  []string
+	}{
+		{
+			name:    "missing mazes",
+			content: "main:\n  port: 8080\n",
+			errors:  []string{"app.mazes must not be empty"},
+		},
+		{
+			name:    "unknown field",
+			content: "app:\n  maze_limit: 1\n" + testMazes,
+			errors:  []string{"field maze_limit not found"},
+		},
+		{
+			name:    "all errors at once",
+			content: "main:\n  port: http\n  read_timeout: 1s\n  read_header_timeout: 2s\n  idle_timeout: 0s\napp:\n  batch_parallelism: -1\n  cache_size: -5\n" + testMazes,
+			errors: []string{
+				`main.port must be a number from 1 to 65535, got "http"`,
+				"main.idle_timeout must be positive",
+				"main.read_header_timeout (2s) must not exceed main.read_timeout (1s)",
+				"app.batch_parallelism must not be negative",
+				"app.cache_size must not be negative, got -5",
+			},
+		},
+		{
+			name: "invalid mazes",
+			content: "app:\n  mazes:\n" +
+				"    - {id: 1, name: small, path: small.txt, original: small.txt}\n" +
+				"    - {id: 1, name: small, path: other.txt}\n" +
+				"    - {id: 0, name: \"42\"}\n" +
+				"    - {id: 3, name: a/b, path: c.txt}\n" +
+				"    - {id: 4, name: d, path: d.txt, landmarks: {count: 100, strategy: random}}\n",
+			errors: []string{
+				`app.mazes[0]: original must differ from path "small.txt"`,
+				"app.mazes[1]: duplicate id 1",
+				`app.mazes[1]: duplicate name "small"`,
+				"app.mazes[2]: id must be positive, got 0",
+				`name "42" must not be a number`,
+				"app.mazes[2]: path must not be empty",
+				`app.mazes[3]: name "a/b" may contain only`,
+				"app.mazes[4]: landmarks.count must be from 0 to 64, got 100",
+				`app.mazes[4]: landmarks.strategy must be farthest or avoid, got "random"`,
+			},
+		},
+		{
+			name:    "invalid env duration",
+			content: "app:\n" + testMazes,
+			env:     map[string]string{"MAIN_IDLE_TIMEOUT": "30"},
+			errors:  []string{`MAIN_IDLE_TIMEOUT: invalid duration "30"`},
+		},
+		{
+			name:    "invalid env integer",
+			content: "app:\n" + testMazes,
+			env:     map[string]string{"APP_BATCH_PARALLELISM": "two"},
+			errors:  []string{`APP_BATCH_PARALLELISM: invalid integer "two"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(writeConfig(t, test.content))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range test.errors {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"algo/algorithms/cbs"
+	"algo/apierror"
+	"algo/handlers/models"
+	"algo/metrics"
+	"algo/utils"
+)
+
+// MultiAgentPathsHandlerV2 ищет пути нескольких агентов в одном лабиринте без столкновений: в каждый момент времени
+// агенты занимают разные клетки и не меняются клетками за один ход
+func (app *App) MultiAgentPathsHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	var req models.MultiAgentInputV2
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
+		return
+	}
+
+	agents, err := buildAgents(board, req.Agents)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to solve maze")
+		return
+	}
+
+	// Поиск останавливается по бюджету или при отключении клиента, тогда ответ сообщает, что решение может существовать
+	searchCtx, cancel := context.WithTimeout(ctx, models.MultiAgentBudget)
+	defer cancel()
+
+	var solution cbs.Solution
+	elapsed := metrics.Solve(cbs.Name, func() int {
+		solution = cbs.Solve(searchCtx, board, agents, cbs.DefaultMaxNodes)
+		return solution.Expanded
+	})
+
+	if err = json.NewEncoder(w).Encode(toMultiAgentOutputV2(solution, elapsed)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+// buildAgents проверяет, что старты и цели агентов не являются стенами
+func buildAgents(board [][]bool, input []models.AgentV2) ([]cbs.Agent, error) {
+	agents := make([]cbs.Agent, len(input))
+	for i, agent := range input {
+		if board[agent.Start.Row][agent.Start.Col] {
+			return nil, apierror.NewInvalidIndexError(apierror.CodeStartIsWall, i, fmt.Sprintf("start cell (row=%d, col=%d) is wall", agent.Start.Row, agent.Start.Col))
+		}
+		if board[agent.Goal.Row][agent.Goal.Col] {
+			return nil, apierror.NewInvalidIndexError(apierror.CodeEndIsWall, i, fmt.Sprintf("goal cell (row=%d, col=%d) is wall", agent.Goal.Row, agent.Goal.Col))
+		}
+		agents[i] = cbs.Agent{Start: [2]int{agent.Start.Row, agent.Start.Col}, Goal: [2]int{agent.Goal.Row, agent.Goal.Col}}
+	}
+
+	return agents, nil
+}
+
+// toMultiAgentOutputV2 формирует ответ, пустой список путей означает, что решение не найдено
+func toMultiAgentOutputV2(solution cbs.Solution, elapsed time.Duration) models.MultiAgentOutputV2 {
+	output := models.MultiAgentOutputV2{
+		Paths:         make([]models.AgentPathV2, len(solution.Paths)),
+		Makespan:      solution.Makespan,
+		SumOfCosts:    solution.SumOfCosts,
+		Expanded:      solution.Expanded,
+		Nodes:         solution.Nodes,
+		LimitReached:  solution.LimitReached,
+		ExecutionTime: elapsed,
+	}
+	if len(solution.Paths) == 0 {
+		output.Makespan, output.SumOfCosts = -1, -1
+	}
+
+	for i, path := range solution.Paths {
+		cells := make([]models.Cell, len(path))
+		for t, cell := range path {
+			cells[t] = models.Cell{Row: cell[0], Col: cell[1]}
+		}
+		output.Paths[i] = models.AgentPathV2{Path: cells, Cost: len(path) - 1}
+	}
+
+	return output
+}
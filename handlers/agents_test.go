@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"algo/apierror"
+	"algo/handlers/models"
+)
+
+func TestMultiAgentPaths(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	// После открытия клетки (2, 2) появляется цикл, и агенты могут поменяться местами: один идет напрямую через (2, 2),
+	// другой обходит цикл через (3, 3), (2, 3) и (1, 3)
+	serve(t, handler, http.MethodPatch, "/api/v2/mazes/1/cells", `{"cells": [{"row": 2, "col": 2, "wall": false}]}`)
+
+	body := `{"agents": [{"start": {"row": 1, "col": 2}, "goal": {"row": 3, "col": 2}}, {"start": {"row": 3, "col": 2}, "goal": {"row": 1, "col": 2}}]}`
+	var output models.MultiAgentOutputV2
+	if err := json.NewDecoder(serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths:multi-agent", body).Body).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Paths) != 2 || output.SumOfCosts != 6 || output.Makespan != 4 {
+		t.Fatalf("got %d paths, sum of costs %d, makespan %d, want 2 paths, 6 and 4", len(output.Paths), output.SumOfCosts, output.Makespan)
+	}
+	for i, path := range output.Paths {
+		if len(path.Path) != path.Cost+1 {
+			t.Errorf("agent %d: %d cells for cost %d", i, len(path.Path), path.Cost)
+		}
+	}
+}
+
+func TestMultiAgentPathsValidation(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, step := range []struct {
+		body, code string
+	}{
+		{body: `{"agents": []}`, code: apierror.CodeInvalidRequest},
+		{body: `{"agents": [{"start": {"row": 1, "col": 1}, "goal": {"row": 5, "col": 1}}]}`, code: apierror.CodeEndOutOfBounds},
+		{body: `{"agents": [{"start": {"row": 0, "col": 0}, "goal": {"row": 1, "col": 1}}]}`, code: apierror.CodeStartIsWall},
+		{body: `{"agents": [{"start": {"row": 1, "col": 1}, "goal": {"row": 4, "col": 1}}, {"start": {"row": 1, "col": 2}, "goal": {"row": 4, "col": 1}}]}`, code: apierror.CodeInvalidRequest},
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths:multi-agent", strings.NewReader(step.body)))
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), step.code) {
+			t.Errorf("%s: status %d, body %s", step.body, recorder.Code, recorder.Body)
+		}
+	}
+}
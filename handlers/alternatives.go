@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"algo/algorithms"
+	"algo/algorithms/k_shortest"
+	"algo/apierror"
+	"algo/handlers/models"
+	"algo/metrics"
+	"algo/utils"
+)
+
+// AlternativePathsHandlerV2 возвращает несколько путей от старта до целей: k кратчайших путей без повторяющихся
+// клеток или непохожие друг на друга пути, найденные методом штрафов
+func (app *App) AlternativePathsHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	var req models.AlternativePathsInputV2
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
+		return
+	}
+
+	targets, err := buildTargets(board, req.Start, req.End)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to solve maze")
+		return
+	}
+
+	k := req.K
+	if k == 0 {
+		k = models.DefaultAlternatives
+	}
+	penalty := req.Penalty
+	if penalty == 0 {
+		penalty = k_shortest.DefaultPenalty
+	}
+
+	name := k_shortest.NameYen
+	if req.Mode == models.AlternativesDiverse {
+		name = k_shortest.NameDiverse
+	}
+	// Поиск останавливается по бюджету или при отключении клиента, найденные к этому моменту пути возвращаются
+	searchCtx, cancel := context.WithTimeout(ctx, models.AlternativesBudget)
+	defer cancel()
+
+	var results []algorithms.Result
+	elapsed := metrics.Solve(name, func() int {
+		if req.Mode == models.AlternativesDiverse {
+			results, err = k_shortest.Diverse(searchCtx, board, req.Start.Row, req.Start.Col, targets, k, penalty)
+		} else {
+			results, err = k_shortest.Yen(searchCtx, board, req.Start.Row, req.Start.Col, targets, k)
+		}
+		if len(results) == 0 {
+			return 0
+		}
+		return results[len(results)-1].Expanded
+	})
+
+	output := models.AlternativePathsOutputV2{Paths: make([]models.AlternativePathV2, len(results)), ExecutionTime: elapsed, Truncated: err != nil}
+	for i, result := range results {
+		output.Paths[i] = models.AlternativePathV2{Path: toCells(result.Path), Dist: result.Dist}
+		output.Expanded = result.Expanded
+	}
+
+	if err = json.NewEncoder(w).Encode(output); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"algo/apierror"
+	"algo/handlers/models"
+)
+
+func TestAlternativePaths(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	// После открытия клетки (2, 2) к цели ведут два пути: через (2, 2) и через (2, 3)
+	serve(t, handler, http.MethodPatch, "/api/v2/mazes/1/cells", `{"cells": [{"row": 2, "col": 2, "wall": false}]}`)
+
+	for _, mode := range []string{models.AlternativesShortest, models.AlternativesDiverse} {
+		body := `{"start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "k": 5, "mode": "` + mode + `"}`
+		var output models.AlternativePathsOutputV2
+		if err := json.NewDecoder(serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths:alternatives", body).Body).Decode(&output); err != nil {
+			t.Fatal(err)
+		}
+		if len(output.Paths) < 2 {
+			t.Fatalf("%s: got %d paths, want at least 2", mode, len(output.Paths))
+		}
+		if first := output.Paths[0]; first.Dist != 6 || len(first.Path) != 7 {
+			t.Errorf("%s: first path dist %d, %d cells, want the shortest path of 6 steps", mode, first.Dist, len(first.Path))
+		}
+	}
+}
+
+func TestAlternativePathsValidation(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, body := range []string{
+		`{"start": {"row": 0, "col": 1}, "k": 11}`,
+		`{"start": {"row": 0, "col": 1}, "mode": "fastest"}`,
+		`{"start": {"row": 0, "col": 1}, "penalty": 2}`,
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths:alternatives", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), apierror.CodeInvalidRequest) {
+			t.Errorf("%s: status %d, body %s", body, recorder.Code, recorder.Body)
+		}
+	}
+}
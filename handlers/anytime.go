@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/ara_star"
+	"algo/apierror"
+	"algo/handlers/models"
+	"algo/metrics"
+	"algo/utils"
+)
+
+// ndjsonContentType тип ответа, в котором каждое решение передается отдельной строкой сразу после нахождения
+const ndjsonContentType = "application/x-ndjson"
+
+// AnytimePathHandlerV2 ищет путь алгоритмом ARA* и возвращает все найденные за бюджет решения, от первого
+// к лучшему. Если клиент принимает application/x-ndjson, каждое решение отправляется сразу после нахождения
+func (app *App) AnytimePathHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	var req models.AnytimePathInputV2
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
+		return
+	}
+
+	targets, err := buildTargets(board, req.Start, req.End)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to solve maze")
+		return
+	}
+
+	options := ara_star.DefaultOptions
+	if req.BudgetMs > 0 {
+		options.Budget = time.Duration(req.BudgetMs) * time.Millisecond
+	}
+	if req.InitialWeight > 0 {
+		options.InitialWeight = req.InitialWeight
+	}
+
+	stream := strings.Contains(r.Header.Get("Accept"), ndjsonContentType)
+	if stream {
+		w.Header().Set("Content-Type", ndjsonContentType)
+	}
+	encoder := json.NewEncoder(w)
+	controller := http.NewResponseController(w)
+
+	output := models.AnytimePathOutputV2{Solutions: []models.AnytimeSolutionV2{}}
+	var result algorithms.Result
+	elapsed := metrics.Solve(ara_star.Name, func() int {
+		result = ara_star.Search(board, req.Start.Row, req.Start.Col, targets, options, func(solution ara_star.Solution) bool {
+			if !stream {
+				output.Solutions = append(output.Solutions, toAnytimeSolutionV2(solution))
+				return ctx.Err() == nil
+			}
+
+			// Клиент, который отключился или не принимает данные, не должен занимать поиск до конца бюджета
+			if err := encoder.Encode(toAnytimeSolutionV2(solution)); err != nil {
+				return false
+			}
+			return controller.Flush() == nil && ctx.Err() == nil
+		})
+		return result.Expanded
+	})
+
+	if stream {
+		// Без последней строки клиент не отличит отсутствие пути от оборванного ответа
+		if result.Dist == algorithms.PathNotFound {
+			notFound := models.AnytimeSolutionV2{Path: []models.Cell{}, Dist: algorithms.PathNotFound, Expanded: result.Expanded, ExecutionTime: elapsed}
+			if err = encoder.Encode(notFound); err != nil {
+				utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+			}
+		}
+		return
+	}
+	if err = encoder.Encode(output); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func toAnytimeSolutionV2(solution ara_star.Solution) models.AnytimeSolutionV2 {
+	return models.AnytimeSolutionV2{
+		Path:          toCells(solution.Path),
+		Dist:          solution.Dist,
+		Weight:        solution.Weight,
+		Bound:         solution.Bound,
+		Expanded:      solution.Expanded,
+		ExecutionTime: solution.Elapsed,
+	}
+}
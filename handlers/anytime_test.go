@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"algo/handlers/models"
+)
+
+func TestAnytimePath(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	const body = `{"start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "initial_weight": 2}`
+	recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths:anytime", body)
+
+	var output models.AnytimePathOutputV2
+	if err := json.NewDecoder(recorder.Body).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Solutions) == 0 {
+		t.Fatal("no solutions")
+	}
+	last := output.Solutions[len(output.Solutions)-1]
+	if last.Dist != 8 || last.Bound != 1 || len(last.Path) != 9 {
+		t.Errorf("last solution: dist %d, bound %g, path %v", last.Dist, last.Bound, last.Path)
+	}
+	if first := output.Solutions[0]; first.Weight != 2 {
+		t.Errorf("first solution weight %g, want 2", first.Weight)
+	}
+}
+
+func TestAnytimePathStream(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths:anytime", strings.NewReader(`{"start": {"row": 0, "col": 1}}`))
+	request.Header.Set("Accept", ndjsonContentType)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != ndjsonContentType {
+		t.Fatalf("status %d, content type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	if !recorder.Flushed {
+		t.Error("solutions must be flushed as soon as they are found")
+	}
+
+	var solutions []models.AnytimeSolutionV2
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var solution models.AnytimeSolutionV2
+		if err := json.Unmarshal(scanner.Bytes(), &solution); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		solutions = append(solutions, solution)
+	}
+	if len(solutions) == 0 || solutions[len(solutions)-1].Bound != 1 {
+		t.Errorf("solutions %+v, want last one with bound 1", solutions)
+	}
+}
+
+func TestAnytimePathStreamNotFound(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	// Стена в (2, 3) разрезает коридор, и от (1, 1) до (4, 1) пути нет
+	serve(t, handler, http.MethodPatch, "/api/v2/mazes/1/cells", `{"cells": [{"row": 2, "col": 3, "wall": true}]}`)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths:anytime", strings.NewReader(`{"start": {"row": 1, "col": 1}, "end": [{"row": 4, "col": 1}]}`))
+	request.Header.Set("Accept", ndjsonContentType)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	var solution models.AnytimeSolutionV2
+	if len(lines) != 1 || json.Unmarshal([]byte(lines[0]), &solution) != nil || solution.Dist != -1 {
+		t.Errorf("status %d, body %q, want one line with dist -1", recorder.Code, recorder.Body)
+	}
+}
+
+func TestAnytimePathValidation(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, body := range []string{
+		`{"start": {"row": 0, "col": 1}, "budget_ms": 60000}`,
+		`{"start": {"row": 0, "col": 1}, "initial_weight": 0.5}`,
+		`{"start": {"row": 0, "col": 0}}`,
+		`{"start": {"row": 9, "col": 1}}`,
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths:anytime", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", body, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "algo/algorithms/lazy_theta_star"
+	"algo/apierror"
+	"algo/config"
+	"algo/handlers/models"
+)
+
+// corridor клетки единственного коридора тестового лабиринта от (0, 1) до выхода (4, 1)
+var corridor = [][2]int{{0, 1}, {1, 1}, {1, 2}, {1, 3}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {4, 1}}
+
+// batchQueries возвращает запросы от каждой клетки коридора до (4, 1) алгоритмами по очереди
+func batchQueries(algorithmIDs ...int) string {
+	queries := make([]string, len(corridor))
+	for i, cell := range corridor {
+		queries[i] = fmt.Sprintf(`{"algorithm_id": %d, "start": {"x": %d, "y": %d}, "end": [{"x": 4, "y": 1}]}`, algorithmIDs[i%len(algorithmIDs)], cell[0], cell[1])
+	}
+	return `{"labirint_id": 1, "coords": "row_col", "queries": [` + strings.Join(queries, ", ") + `]}`
+}
+
+// checkBatchOrder проверяет, что i-й результат относится к i-му запросу: от i-й клетки коридора до выхода 8-i шагов
+func checkBatchOrder(t *testing.T, body string) {
+	t.Helper()
+
+	var output models.BatchSolveMazeOutput
+	if err := json.Unmarshal([]byte(body), &output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Results) != len(corridor) {
+		t.Fatalf("got %d results for %d queries", len(output.Results), len(corridor))
+	}
+	for i, result := range output.Results {
+		if result.Error != nil || result.Result == nil {
+			t.Fatalf("query %d: error %+v", i, result.Error)
+		}
+		if want := len(corridor) - 1 - i; result.Result.Dist != want {
+			t.Errorf("query %d: dist %d, want %d", i, result.Result.Dist, want)
+		}
+	}
+}
+
+func TestBatchKeepsOrder(t *testing.T) {
+	handler := newTestAppWith(t, config.AppConfig{BatchParallelism: 4})
+
+	recorder := serve(t, handler, http.MethodPost, "/api/v1/calc_path/batch", batchQueries(1))
+	checkBatchOrder(t, recorder.Body.String())
+}
+
+func TestBatchQueryErrors(t *testing.T) {
+	handler := newTestAppWith(t, config.AppConfig{BatchParallelism: 2})
+
+	body := `{"labirint_id": 1, "coords": "row_col", "queries": [
+		{"algorithm_id": 1, "start": {"x": 0, "y": 1}, "end": [{"x": 4, "y": 1}]},
+		{"algorithm_id": 1, "start": {"x": 0, "y": 1}, "end": [{"x": 4, "y": 1}, {"x": 0, "y": 0}]},
+		{"algorithm_id": 99, "start": {"x": 0, "y": 1}},
+		{"algorithm_id": 1, "start": {"x": 0, "y": 1}, "end": [{"x": 4, "y": 1}, {"x": 4, "y": 1}, {"x": 9, "y": 9}]}
+	]}`
+	var output models.BatchSolveMazeOutput
+	if err := json.NewDecoder(serve(t, handler, http.MethodPost, "/api/v1/calc_path/batch", body).Body).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ошибка одного запроса не мешает остальным, а index указывает на элемент end внутри запроса
+	want := []struct {
+		code  string
+		index int // -1 — без индекса
+	}{
+		{},
+		{code: apierror.CodeEndIsWall, index: 1},
+		{code: apierror.CodeInvalidAlgorithmID, index: -1},
+		{code: apierror.CodeEndOutOfBounds, index: 2},
+	}
+	if len(output.Results) != len(want) {
+		t.Fatalf("got %d results for %d queries", len(output.Results), len(want))
+	}
+	for i, result := range output.Results {
+		if want[i].code == "" {
+			if result.Error != nil || result.Result == nil || result.Result.Dist != 8 {
+				t.Errorf("query %d: result %+v, error %+v, want dist 8", i, result.Result, result.Error)
+			}
+			continue
+		}
+		if result.Error == nil || result.Error.Code != want[i].code {
+			t.Errorf("query %d: error %+v, want code %s", i, result.Error, want[i].code)
+			continue
+		}
+		if got := result.Error.Index; (want[i].index == -1) != (got == nil) || (got != nil && *got != want[i].index) {
+			t.Errorf("query %d: index %v, want %d", i, got, want[i].index)
+		}
+	}
+}
+
+func TestBatchMaxQueries(t *testing.T) {
+	handler := newTestAppWith(t, config.AppConfig{BatchMaxQueries: len(corridor) - 1})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/calc_path/batch", strings.NewReader(batchQueries(1))))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), apierror.CodeTooManyQueries) {
+		t.Errorf("status %d, body %s, want %s", recorder.Code, recorder.Body, apierror.CodeTooManyQueries)
+	}
+
+	handler = newTestAppWith(t, config.AppConfig{BatchMaxQueries: len(corridor)})
+	serve(t, handler, http.MethodPost, "/api/v1/calc_path/batch", batchQueries(1))
+}
+
+// TestBatchConcurrent запускает несколько пакетов одновременно с разными алгоритмами, включая Lazy Theta*,
+// чтобы go test -race нашел общее состояние решателей
+func TestBatchConcurrent(t *testing.T) {
+	handler := newTestAppWith(t, config.AppConfig{BatchParallelism: 4})
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 8)
+	for i := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/calc_path/batch", strings.NewReader(batchQueries(1, 2, 3))))
+			bodies[i] = recorder.Body.String()
+		}()
+	}
+	wg.Wait()
+
+	for _, body := range bodies {
+		checkBatchOrder(t, body)
+	}
+}
@@ -0,0 +1,15 @@
+// Package docs содержит спецификацию OpenAPI и страницу документации, встроенные в бинарный файл сервиса
+package docs
+
+import "embed"
+
+// FS содержит openapi.json, index.html и файлы страницы документации
+//
+//go:embed openapi.json index.html docs.js docs.css
+var FS embed.FS
+
+// SpecFile имя файла спецификации в FS
+const SpecFile = "openapi.json"
+
+// IndexFile имя файла страницы документации в FS
+const IndexFile = "index.html"
@@ -0,0 +1,238 @@
+'use strict';
+
+// Страница загружает спецификацию и для каждой операции показывает параметры, схемы запроса и ответов,
+// а также форму для отправки запроса. Скрипт подключается отдельным файлом из-за Content-Security-Policy
+
+const methods = ['get', 'post', 'patch', 'put', 'delete'];
+
+function element(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) {
+        node.className = className;
+    }
+    if (text !== undefined) {
+        node.textContent = text;
+    }
+    return node;
+}
+
+function resolve(spec, value) {
+    while (value && value.$ref) {
+        const path = value.$ref.replace(/^#\//, '').split('/');
+        value = path.reduce((node, key) => node[key], spec);
+    }
+    return value;
+}
+
+// example строит пример значения по схеме
+function example(spec, schema, depth) {
+    schema = resolve(spec, schema);
+    if (!schema || depth > 8) {
+        return null;
+    }
+    if (schema.default !== undefined) {
+        return schema.default;
+    }
+    if (schema.enum) {
+        return schema.enum[0];
+    }
+    switch (schema.type) {
+    case 'object': {
+        const result = {};
+        for (const [name, property] of Object.entries(schema.properties || {})) {
+            if ((schema.required || []).includes(name)) {
+                result[name] = example(spec, property, depth + 1);
+            }
+        }
+        return result;
+    }
+    case 'array':
+        return [example(spec, schema.items, depth + 1)];
+    case 'integer':
+        return 0;
+    case 'boolean':
+        return false;
+    default:
+        return '';
+    }
+}
+
+// describe возвращает текстовое описание схемы в виде, похожем на TypeScript
+function describe(spec, schema, indent, seen) {
+    if (schema.$ref) {
+        const name = schema.$ref.split('/').pop();
+        if (seen.includes(name)) {
+            return name;
+        }
+        return name + ' ' + describe(spec, resolve(spec, schema), indent, seen.concat(name));
+    }
+    if (schema.type === 'array') {
+        return describe(spec, schema.items, indent, seen) + '[]' + (schema.nullable ? ' | null' : '');
+    }
+    if (schema.type === 'object' && schema.properties) {
+        const pad = '  '.repeat(indent + 1);
+        const lines = Object.entries(schema.properties).map(([name, property]) => {
+            const optional = (schema.required || []).includes(name) ? '' : '?';
+            const comment = property.description ? ' // ' + property.description : '';
+            return pad + name + optional + ': ' + describe(spec, property, indent + 1, seen) + comment;
+        });
+        return '{\n' + lines.join('\n') + '\n' + '  '.repeat(indent) + '}';
+    }
+    if (schema.enum) {
+        return schema.enum.map((value) => JSON.stringify(value)).join(' | ');
+    }
+    return schema.type || 'any';
+}
+
+function renderSchema(spec, title, schema) {
+    const section = element('div');
+    section.appendChild(element('h4', '', title));
+    section.appendChild(element('pre', '', describe(spec, schema, 0, [])));
+    return section;
+}
+
+function renderTryIt(spec, path, method, operation) {
+    const form = element('form');
+    form.appendChild(element('h4', '', 'Отправить запрос'));
+
+    const parameters = (operation.parameters || []).map((parameter) => resolve(spec, parameter));
+    const inputs = {};
+    for (const parameter of parameters) {
+        const label = element('label', '', parameter.name + ' (' + parameter.in + '): ');
+        const input = element('input');
+        input.name = parameter.name;
+        input.value = parameter.schema && parameter.schema.enum ? parameter.schema.enum[0] : '1';
+        inputs[parameter.name] = {parameter: parameter, input: input};
+        label.appendChild(input);
+        form.appendChild(label);
+        form.appendChild(element('br'));
+    }
+
+    let body = null;
+    const content = operation.requestBody && operation.requestBody.content['application/json'];
+    if (content) {
+        body = element('textarea');
+        body.value = JSON.stringify(example(spec, content.schema, 0), null, 2);
+        form.appendChild(body);
+    }
+
+    const button = element('button', '', 'Отправить');
+    button.type = 'submit';
+    form.appendChild(button);
+
+    const status = element('p', 'status');
+    const output = element('pre');
+    form.appendChild(status);
+    form.appendChild(output);
+
+    form.addEventListener('submit', async (event) => {
+        event.preventDefault();
+
+        let url = path;
+        const query = new URLSearchParams();
+        for (const {parameter, input} of Object.values(inputs)) {
+            if (parameter.in === 'path') {
+                url = url.replace('{' + parameter.name + '}', encodeURIComponent(input.value));
+            } else if (parameter.in === 'query') {
+                query.set(parameter.name, input.value);
+            }
+        }
+        if (query.toString()) {
+            url += '?' + query.toString();
+        }
+
+        const init = {method: method.toUpperCase()};
+        if (body) {
+            init.headers = {'Content-Type': 'application/json'};
+            init.body = body.value;
+        }
+
+        try {
+            const response = await fetch(url, init);
+            const text = await response.text();
+            status.textContent = response.status + ' ' + response.statusText;
+            try {
+                output.textContent = JSON.stringify(JSON.parse(text), null, 2);
+            } catch (e) {
+                output.textContent = text;
+            }
+        } catch (e) {
+            status.textContent = 'Ошибка';
+            output.textContent = String(e);
+        }
+    });
+
+    return form;
+}
+
+function renderOperation(spec, path, method, operation) {
+    const details = element('details');
+
+    const summary = element('summary');
+    summary.appendChild(element('span', 'method method-' + method, method.toUpperCase()));
+    summary.appendChild(element('span', 'path', path));
+    summary.appendChild(document.createTextNode(' ' + (operation.summary || '')));
+    details.appendChild(summary);
+
+    const body = element('div', 'operation-body');
+    if (operation.description) {
+        body.appendChild(element('p', '', operation.description));
+    }
+
+    const parameters = (operation.parameters || []).map((parameter) => resolve(spec, parameter));
+    if (parameters.length > 0) {
+        body.appendChild(element('h4', '', 'Параметры'));
+        const list = element('ul');
+        for (const parameter of parameters) {
+            const text = parameter.name + ' (' + parameter.in + (parameter.required ? ', обязательный' : '') + ')' +
+                (parameter.description ? ' — ' + parameter.description : '');
+            list.appendChild(element('li', '', text));
+        }
+        body.appendChild(list);
+    }
+
+    const request = operation.requestBody && operation.requestBody.content['application/json'];
+    if (request) {
+        body.appendChild(renderSchema(spec, 'Тело запроса', request.schema));
+    }
+
+    for (const [code, value] of Object.entries(operation.responses || {})) {
+        const response = resolve(spec, value);
+        const content = response.content && response.content['application/json'];
+        if (content) {
+            body.appendChild(renderSchema(spec, code + ': ' + response.description, content.schema));
+        } else {
+            body.appendChild(element('h4', '', code + ': ' + response.description));
+        }
+    }
+
+    body.appendChild(renderTryIt(spec, path, method, operation));
+    details.appendChild(body);
+    return details;
+}
+
+async function main() {
+    const response = await fetch('openapi.json');
+    const spec = await response.json();
+
+    document.title = spec.info.title + ' API';
+    document.getElementById('title').textContent = spec.info.title + ' API ' + spec.info.version;
+    document.getElementById('description').textContent = spec.info.description || '';
+
+    const container = document.getElementById('operations');
+    for (const tag of spec.tags || []) {
+        const section = element('section');
+        section.appendChild(element('h2', '', tag.name + ' — ' + tag.description));
+        for (const [path, item] of Object.entries(spec.paths)) {
+            for (const method of methods) {
+                const operation = item[method];
+                if (operation && (operation.tags || []).includes(tag.name)) {
+                    section.appendChild(renderOperation(spec, path, method, operation));
+                }
+            }
+        }
+        container.appendChild(section);
+    }
+}
+
+main();
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"sync"
+
+	"algo/algorithms/exit_distance"
+)
+
+// exitFields хранит поля расстояний до выходов для текущих версий лабиринтов
+type exitFields struct {
+	mu     sync.Mutex
+	fields map[int]exitField
+}
+
+type exitField struct {
+	version uint64
+	field   *exit_distance.Field
+}
+
+func newExitFields() *exitFields {
+	return &exitFields{fields: make(map[int]exitField)}
+}
+
+// get возвращает поле для версии лабиринта, строя его заново, если лабиринт изменился
+func (store *exitFields) get(mazeID int, version uint64, board [][]bool) *exit_distance.Field {
+	store.mu.Lock()
+	cached, found := store.fields[mazeID]
+	store.mu.Unlock()
+
+	if found && cached.version == version {
+		return cached.field
+	}
+
+	// Поле строится без блокировки, одновременные запросы к измененному лабиринту могут построить его дважды
+	field := exit_distance.Compute(board)
+
+	store.mu.Lock()
+	store.fields[mazeID] = exitField{version: version, field: field}
+	store.mu.Unlock()
+
+	return field
+}
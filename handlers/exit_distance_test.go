@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"algo/handlers/models"
+)
+
+func TestExitDistance(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	exitDistance := func() models.ExitDistanceOutputV2 {
+		var resp models.ExitDistanceOutputV2
+		recorder := serve(t, handler, http.MethodGet, "/api/v2/mazes/small/exit_distance", "")
+		if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := exitDistance()
+	if resp.ID != 1 || resp.Rows != 5 || resp.Cols != 5 || resp.Max != 4 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if resp.Dist[0][1] != 0 || resp.Dist[1][3] != 3 || resp.Dist[2][3] != 4 || resp.Dist[2][2] != -1 {
+		t.Errorf("unexpected distances %v", resp.Dist)
+	}
+
+	// Путь до ближайшего выхода берется из поля и совпадает с его значением
+	var path models.FindPathOutputV2
+	recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", `{"algorithm_id": 1, "start": {"row": 2, "col": 3}}`)
+	if err := json.NewDecoder(recorder.Body).Decode(&path); err != nil {
+		t.Fatal(err)
+	}
+	if path.Dist != 4 || len(path.Path) != 5 {
+		t.Errorf("path from (2,3): dist %d with %d cells, want 4 with 5 cells", path.Dist, len(path.Path))
+	}
+
+	// После изменения лабиринта поле перестраивается
+	serve(t, handler, http.MethodPatch, "/api/v2/mazes/1/cells", `{"cells": [{"row": 2, "col": 2, "wall": false}]}`)
+	if resp = exitDistance(); resp.Dist[2][2] != 3 {
+		t.Errorf("dist of opened cell = %d, want 3", resp.Dist[2][2])
+	}
+}
@@ -1,234 +1,306 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
-	"path"
-	"strconv"
-	"strings"
-	"time"
-
-	"algo/algorithms"
-	"algo/algorithms/a_star"
-	"algo/algorithms/lazy_theta_star"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"algo/apierror"
+	"algo/cache"
 	"algo/config"
 	"algo/handlers/models"
 	"algo/maze"
+	"algo/metrics"
 	"algo/utils"
 )
 
 type App struct {
-	cfg config.AppConfig
+	state   atomic.Pointer[appState]
+	cache   *cache.LRU[solveKey, solution] // Кэш результатов поиска пути, общий для всех версий конфигурации
+	exits   *exitFields                    // Поля расстояний до выходов, строятся при первом обращении и после изменения лабиринта
+	indexes *indexes                       // Индексы алгоритмов с предобработкой, строятся при первом запросе и обновляются при изменении лабиринта
+}
+
+func NewApp(cfg config.AppConfig) (*App, error) {
+	app := &App{
+		cache:   cache.NewLRU[solveKey, solution](cfg.CacheSize),
+		exits:   newExitFields(),
+		indexes: newIndexes(),
+	}
+	if err := app.SetConfig(cfg); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// SetConfig заменяет конфигурацию и каталог лабиринтов. Запросы, которые уже обрабатываются, завершаются со старой конфигурацией.
+// Если каталог построить не удалось, продолжает действовать прежняя конфигурация
+func (app *App) SetConfig(cfg config.AppConfig) error {
+	catalog, err := maze.NewCatalog(cfg.Mazes)
+	if err != nil {
+		return err
+	}
+
+	app.preprocessMazes(catalog)
+	app.state.Store(&appState{cfg: cfg, catalog: catalog})
+	app.cache.Resize(cfg.CacheSize)
+	metrics.SetCacheEntries(app.cache.Len())
+	return nil
 }
 
-func NewApp(cfg config.AppConfig) *App {
-	return &App{cfg}
+// current возвращает текущую конфигурацию, обработчик должен получить ее один раз в начале запроса
+func (app *App) current() *appState {
+	return app.state.Load()
 }
 
 func (app *App) SolveMazeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	state := app.current()
 
 	var req models.SolveMazeInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrUnmarshalRequest)
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
 		return
 	}
 
-	board, err := maze.ParseMaze(os.Getenv(fmt.Sprintf("MAZE_FILE_%d", req.MazeID)))
+	entry, err := state.resolveMaze(req.MazeID)
 	if err != nil {
-		utils.LogError(ctx, err, "failed to parse maze")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, "invalid maze id")
 		return
 	}
 
-	if err = req.Validate(app.cfg, len(board[0]), len(board)); err != nil {
-		utils.LogError(ctx, err, "failed to validate maze")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
 		return
 	}
 
-	if board[req.Start.X][req.Start.Y] {
-		utils.LogErrorMessage(ctx, fmt.Sprintf("start point (%d,%d)=1, it is wall", req.Start.X, req.Start.Y))
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
 		return
 	}
 
-	boundaryCells := make([][2]int, 0)
-	if len(req.End) == 0 {
-		boundaryCells = algorithms.GetBoundaryCells(board, req.Start.X, req.Start.Y)
-	} else {
-		for _, end := range req.End {
-			if board[end.X][end.Y] {
-				utils.LogErrorMessage(ctx, fmt.Sprintf("end point (%d,%d)=1, it is wall", end.X, end.Y))
-				http.Error(w, utils.Invalid, http.StatusBadRequest)
-				return
-			}
-			boundaryCells = append(boundaryCells, [2]int{end.X, end.Y})
-		}
+	start, end, via := req.Coords.Cell(req.Start), req.Coords.Cells(req.End), req.Coords.Cells(req.Via)
+	key := newSolveKey(entry.ID, maze.Version(board), req.AlgorithmID, req.HeuristicWeight, req.Heuristic, req.Movement, start, end).withVia(via, req.ViaOrder)
+	sol, cached, err := app.solveCached(key, board, start, end, via)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to solve maze")
+		return
 	}
 
-	var (
-		result       models.SolveMazeOutput
-		distance     int
-		shortestPath []algorithms.Node
-	)
+	setCacheHeader(w, cached)
+	if err = json.NewEncoder(w).Encode(toSolveMazeOutput(sol, req.Coords)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) SolveMazeBatchHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
 
-	startTime := time.Now()
-	switch req.AlgorithmID {
-	case 1:
-		distance, shortestPath = a_star.AStar(board, req.Start.X, req.Start.Y, boundaryCells)
-	case 2:
-		distance, shortestPath = lazy_theta_star.LazyThetaStar(board, req.Start.X, req.Start.Y, boundaryCells)
-	default:
-		utils.LogErrorMessage(ctx, fmt.Sprintf("invalid algorithm id=%d", req.AlgorithmID))
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	var req models.BatchSolveMazeInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
 		return
 	}
-	endTime := time.Now()
 
-	if distance == algorithms.PathNotFound {
-		if err = json.NewEncoder(w).Encode(result); err != nil {
-			utils.LogError(ctx, err, utils.MsgErrMarshalResponse)
-			http.Error(w, utils.Internal, http.StatusInternalServerError)
-			return
-		}
+	if err := req.Validate(state.cfg); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate batch")
+		return
+	}
+
+	entry, err := state.resolveMaze(req.MazeID)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
 		return
 	}
 
-	if len(shortestPath) == 0 {
-		utils.LogErrorMessage(ctx, "path is empty")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
 		return
 	}
 
-	result.Path = make([]models.Tranzition, len(shortestPath)-1)
-	result.Dist = distance
-	result.ExecutionTime = endTime.Sub(startTime)
+	resp := models.BatchSolveMazeOutput{Results: make([]models.BatchResult, len(req.Queries))}
+	version := maze.Version(board)
 
-	for i := 1; i < len(shortestPath); i++ {
-		result.Path[i-1] = models.Tranzition{
-			Start: models.Point{X: shortestPath[i-1].X, Y: shortestPath[i-1].Y},
-			End:   models.Point{X: shortestPath[i].X, Y: shortestPath[i].Y},
-		}
+	parallelism := state.cfg.BatchParallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	sem := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+	for i, query := range req.Queries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resp.Results[i] = app.solveBatchQuery(ctx, i, entry.ID, version, board, req.Coords, query)
+		}()
 	}
+	wg.Wait()
 
-	if err = json.NewEncoder(w).Encode(result); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrMarshalResponse)
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
 		return
 	}
 }
 
-func (app *App) UpdateMazeHandler(w http.ResponseWriter, r *http.Request) {
+func (app *App) solveBatchQuery(ctx context.Context, index int, mazeID int, version uint64, board [][]bool, coords models.Coords, query models.BatchQuery) models.BatchResult {
+	err := query.Validate(coords, len(board), len(board[0]))
+	if err != nil {
+		return batchError(ctx, index, err)
+	}
+
+	start, end := coords.Cell(query.Start), coords.Cells(query.End)
+	sol, _, err := app.solveCached(newSolveKey(mazeID, version, query.AlgorithmID, 0, "", "", start, end), board, start, end, nil)
+	if err != nil {
+		return batchError(ctx, index, err)
+	}
+
+	result := toSolveMazeOutput(sol, coords)
+	return models.BatchResult{Result: &result}
+}
+
+func batchError(ctx context.Context, index int, err error) models.BatchResult {
+	utils.LogError(ctx, err, fmt.Sprintf("batch query %d failed", index))
+
+	_, body := utils.ErrorBody(err)
+	return models.BatchResult{Error: &body}
+}
+
+func (app *App) CompareHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	state := app.current()
 
-	var req models.UpdateMazeInput
+	var req models.CompareInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrUnmarshalRequest)
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
 		return
 	}
 
-	filename := os.Getenv(fmt.Sprintf("MAZE_FILE_%d", req.MazeID))
+	entry, err := state.resolveMaze(req.MazeID)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
 
-	board, err := maze.ParseMaze(filename)
+	board, err := entry.Load()
 	if err != nil {
-		utils.LogError(ctx, err, "failed to parse maze")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, "failed to parse maze")
 		return
 	}
 
-	if err = req.Validate(app.cfg, len(board[0]), len(board)); err != nil {
-		utils.LogError(ctx, err, "failed to validate maze")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
 		return
 	}
 
-	newBoard, err := maze.UpdateMaze(filename, board, req.Points)
+	resp, err := compareAlgorithms(board, req.Coords.Cell(req.Start), req.Coords.Cells(req.End))
 	if err != nil {
-		utils.LogError(ctx, err, "failed to update maze")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, "failed to compare algorithms")
 		return
 	}
 
-	resp := models.UpdateMazeOutput{Map: toIntMap(newBoard)}
 	if err = json.NewEncoder(w).Encode(resp); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrMarshalResponse)
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
 		return
 	}
 }
 
-func (app *App) GetMazeHandler(w http.ResponseWriter, r *http.Request) {
+func (app *App) UpdateMazeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	state := app.current()
 
-	mazeIDString := r.URL.Query().Get("labirint_id")
-	mazeID, err := strconv.ParseInt(mazeIDString, 10, 64)
+	var req models.UpdateMazeInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	entry, err := state.resolveMaze(req.MazeID)
 	if err != nil {
-		utils.LogError(ctx, err, "failed to parse maze id")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+		utils.WriteError(ctx, w, err, "invalid maze id")
 		return
 	}
 
-	req := models.GetMazeInput{MazeID: int(mazeID)}
-	if err = req.Validate(app.cfg); err != nil {
-		utils.LogError(ctx, err, "invalid maze id")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
 		return
 	}
 
-	filename := os.Getenv(fmt.Sprintf("MAZE_FILE_%d", req.MazeID))
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
+		return
+	}
 
-	board, err := maze.ParseMaze(filename)
+	newBoard, err := app.updateMaze(entry, board, req.Coords.Cells(req.Points))
 	if err != nil {
-		utils.LogError(ctx, err, "failed to parse maze")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, "failed to update maze")
 		return
 	}
 
-	resp := models.GetMazeOutput{Map: toIntMap(board)}
+	resp := models.UpdateMazeOutput{Map: toIntMap(newBoard)}
 	if err = json.NewEncoder(w).Encode(resp); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrMarshalResponse)
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
 		return
 	}
 }
 
-func (app *App) RestoreMazeHandler(w http.ResponseWriter, r *http.Request) {
+func (app *App) GetMazeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	state := app.current()
 
-	mazeIDString := r.URL.Query().Get("labirint_id")
-	mazeID, err := strconv.ParseInt(mazeIDString, 10, 64)
+	req := models.GetMazeInput{MazeID: models.MazeRef(r.URL.Query().Get("labirint_id"))}
+	entry, err := state.resolveMaze(req.MazeID)
 	if err != nil {
-		utils.LogError(ctx, err, "failed to parse maze id")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+		utils.WriteError(ctx, w, err, "invalid maze id")
 		return
 	}
 
-	req := models.GetMazeInput{MazeID: int(mazeID)}
-	if err = req.Validate(app.cfg); err != nil {
-		utils.LogError(ctx, err, "invalid maze id")
-		http.Error(w, utils.Invalid, http.StatusBadRequest)
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
 		return
 	}
 
-	filename := os.Getenv(fmt.Sprintf("MAZE_FILE_%d", req.MazeID))
-	originalFilename := getOriginalFilename(filename)
+	resp := models.GetMazeOutput{Map: toIntMap(board)}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
 
-	board, err := maze.RestoreMaze(filename, originalFilename)
+func (app *App) RestoreMazeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	req := models.RestoreMazeInput{MazeID: models.MazeRef(r.URL.Query().Get("labirint_id"))}
+	entry, err := state.resolveMaze(req.MazeID)
 	if err != nil {
-		utils.LogError(ctx, err, "failed to update maze")
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, "invalid maze id")
 		return
 	}
 
-	resp := models.UpdateMazeOutput{Map: toIntMap(board)}
+	board, err := app.restoreMaze(entry)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to restore maze")
+		return
+	}
+
+	resp := models.RestoreMazeOutput{Map: toIntMap(board)}
 	if err = json.NewEncoder(w).Encode(resp); err != nil {
-		utils.LogError(ctx, err, utils.MsgErrMarshalResponse)
-		http.Error(w, utils.Internal, http.StatusInternalServerError)
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
 		return
 	}
 }
@@ -248,8 +320,3 @@ func toIntMap(board [][]bool) [][]int {
 
 	return result
 }
-
-func getOriginalFilename(filename string) string {
-	ext := path.Ext(filename)
-	return strings.TrimSuffix(filename, ext) + "_default" + ext
-}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"algo/apierror"
+	"algo/handlers/models"
+	"algo/maze"
+	"algo/utils"
+	"github.com/gorilla/mux"
+)
+
+func (app *App) ListMazesHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entries := state.catalog.All()
+	resp := models.ListMazesOutputV2{Mazes: make([]models.MazeSummaryV2, 0, len(entries))}
+	for _, entry := range entries {
+		board, err := entry.Load()
+		if err != nil {
+			utils.WriteError(ctx, w, err, "failed to parse maze")
+			return
+		}
+
+		resp.Mazes = append(resp.Mazes, models.MazeSummaryV2{
+			ID:          entry.ID,
+			Name:        entry.Name,
+			Description: entry.Description,
+			Format:      string(entry.Format),
+			ReadOnly:    entry.ReadOnly,
+			Restorable:  entry.Restorable(),
+			Rows:        len(board),
+			Cols:        len(board[0]),
+			Landmarks:   toLandmarksV2(app.landmarks(entry, board)),
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) GetMazeHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(app.toMazeOutputV2(entry, board)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) PatchCellsHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	var req models.PatchCellsInputV2
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate cells")
+		return
+	}
+
+	newBoard, err := app.updateMaze(entry, board, changedCells(board, req.Cells))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to update maze")
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(app.toMazeOutputV2(entry, newBoard)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) RestoreMazeHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	board, err := app.restoreMaze(entry)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to restore maze")
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(app.toMazeOutputV2(entry, board)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) FindPathHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	var req models.FindPathInputV2
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(ctx, w, apierror.NewInvalidError(apierror.CodeInvalidRequest, err.Error()), utils.MsgErrUnmarshalRequest)
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	if err = req.Validate(len(board), len(board[0])); err != nil {
+		utils.WriteError(ctx, w, err, "failed to validate maze")
+		return
+	}
+
+	key := newSolveKey(entry.ID, maze.Version(board), req.AlgorithmID, req.HeuristicWeight, req.Heuristic, req.Movement, req.Start, req.End).withVia(req.Via, req.ViaOrder)
+	sol, cached, err := app.solveCached(key, board, req.Start, req.End, req.Via)
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to solve maze")
+		return
+	}
+
+	setCacheHeader(w, cached)
+	if err = json.NewEncoder(w).Encode(toFindPathOutputV2(sol)); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) ExitDistanceHandlerV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state := app.current()
+
+	entry, err := state.resolveMaze(mazeRefFromPath(r))
+	if err != nil {
+		utils.WriteError(ctx, w, err, "invalid maze id")
+		return
+	}
+
+	board, err := entry.Load()
+	if err != nil {
+		utils.WriteError(ctx, w, err, "failed to parse maze")
+		return
+	}
+
+	field := app.exits.get(entry.ID, maze.Version(board), board)
+	resp := models.ExitDistanceOutputV2{
+		ID:   entry.ID,
+		Name: entry.Name,
+		Rows: len(board),
+		Cols: len(board[0]),
+		Max:  field.Max,
+		Dist: field.Dist,
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+// mazeRefFromPath возвращает ссылку на лабиринт из пути запроса
+func mazeRefFromPath(r *http.Request) models.MazeRef {
+	return models.MazeRef(mux.Vars(r)["id"])
+}
+
+// changedCells возвращает клетки, значение которых отличается от запрошенного
+func changedCells(board [][]bool, patches []models.CellPatchV2) []models.Cell {
+	wanted := make(map[models.Cell]bool, len(patches))
+	order := make([]models.Cell, 0, len(patches))
+	for _, patch := range patches {
+		cell := models.Cell{Row: patch.Row, Col: patch.Col}
+		if _, found := wanted[cell]; !found {
+			order = append(order, cell)
+		}
+		wanted[cell] = patch.Wall
+	}
+
+	cells := make([]models.Cell, 0, len(order))
+	for _, cell := range order {
+		if board[cell.Row][cell.Col] != wanted[cell] {
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells
+}
+
+func (app *App) toMazeOutputV2(entry maze.Entry, board [][]bool) models.MazeOutputV2 {
+	return models.MazeOutputV2{
+		ID:        entry.ID,
+		Name:      entry.Name,
+		Rows:      len(board),
+		Cols:      len(board[0]),
+		Cells:     toIntMap(board),
+		Landmarks: toLandmarksV2(app.landmarks(entry, board)),
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"algo/algorithms"
+	"algo/buildinfo"
+	"algo/handlers/models"
+	"algo/maze"
+	"algo/utils"
+	"github.com/pkg/errors"
+)
+
+// HealthHandler сообщает, что процесс жив и обрабатывает запросы
+func (app *App) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := json.NewEncoder(w).Encode(models.HealthOutput{Status: models.StatusOK}); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+// ReadyHandler проверяет, что конфигурация корректна, а все лабиринты читаются.
+// Если хотя бы одна проверка не прошла, возвращается 503
+func (app *App) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp := models.ReadyOutput{Status: models.StatusOK, Checks: app.readyChecks()}
+	for _, check := range resp.Checks {
+		if !check.OK {
+			resp.Status = models.StatusNotReady
+			utils.LogErrorMessage(ctx, fmt.Sprintf("readiness check %s failed: %s", check.Name, check.Error))
+		}
+	}
+
+	if resp.Status != models.StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
+
+func (app *App) readyChecks() []models.ReadyCheck {
+	state := app.current()
+
+	checks := []models.ReadyCheck{
+		newReadyCheck("config", state.cfg.Validate()),
+		newReadyCheck("algorithms", checkAlgorithms()),
+	}
+
+	for _, entry := range state.catalog.All() {
+		checks = append(checks, newReadyCheck(fmt.Sprintf("maze_%d", entry.ID), checkMaze(entry)))
+	}
+
+	return checks
+}
+
+func newReadyCheck(name string, err error) models.ReadyCheck {
+	if err != nil {
+		return models.ReadyCheck{Name: name, OK: false, Error: err.Error()}
+	}
+
+	return models.ReadyCheck{Name: name, OK: true}
+}
+
+func checkAlgorithms() error {
+	if len(algorithms.All()) == 0 {
+		return errors.New("no algorithms registered")
+	}
+
+	return nil
+}
+
+// checkMaze проверяет, что читаются текущая и исходная версии лабиринта
+func checkMaze(entry maze.Entry) error {
+	if _, err := entry.Load(); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", entry.Path)
+	}
+
+	if entry.Restorable() {
+		if _, err := maze.LoadMaze(entry.Original, entry.Format); err != nil {
+			return errors.Wrapf(err, "failed to parse original %s", entry.Original)
+		}
+	}
+
+	return nil
+}
+
+// VersionHandler возвращает сведения о сборке и список зарегистрированных алгоритмов
+func (app *App) VersionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	info := buildinfo.Get()
+	resp := models.VersionOutput{
+		Commit:     info.Commit,
+		BuildTime:  info.BuildTime,
+		Modified:   info.Modified,
+		GoVersion:  info.GoVersion,
+		Algorithms: []models.AlgorithmInfo{},
+	}
+	for _, algorithm := range algorithms.All() {
+		resp.Algorithms = append(resp.Algorithms, models.AlgorithmInfo{
+			ID:          algorithm.ID,
+			Name:        algorithm.Name,
+			Title:       algorithm.Title,
+			AnyAngle:    algorithm.AnyAngle,
+			Approximate: algorithm.Approximate,
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WriteError(ctx, w, err, utils.MsgErrMarshalResponse)
+		return
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"sync"
+
+	"algo/algorithms"
+)
+
+// indexes хранит индексы алгоритмов с предобработкой для текущих версий лабиринтов
+type indexes struct {
+	mu      sync.Mutex
+	indexes map[indexKey]versionedIndex
+}
+
+type indexKey struct {
+	mazeID      int
+	algorithmID int
+}
+
+type versionedIndex struct {
+	version uint64
+	index   algorithms.Index
+}
+
+func newIndexes() *indexes {
+	return &indexes{indexes: make(map[indexKey]versionedIndex)}
+}
+
+// get возвращает индекс алгоритма для версии лабиринта. Индекс предыдущей версии обновляется,
+// а не строится заново
+func (store *indexes) get(mazeID int, version uint64, algorithm algorithms.Algorithm, board [][]bool) algorithms.Index {
+	key := indexKey{mazeID: mazeID, algorithmID: algorithm.ID}
+
+	store.mu.Lock()
+	cached, found := store.indexes[key]
+	store.mu.Unlock()
+
+	if found && cached.version == version {
+		return cached.index
+	}
+
+	// Индекс строится без блокировки, одновременные запросы к измененному лабиринту могут построить его дважды
+	var index algorithms.Index
+	if found {
+		index = cached.index.Update(board)
+	} else {
+		index = algorithm.Preprocess(board)
+	}
+
+	store.mu.Lock()
+	store.indexes[key] = versionedIndex{version: version, index: index}
+	store.mu.Unlock()
+
+	return index
+}
+
+// put заменяет индекс алгоритма для версии лабиринта
+func (store *indexes) put(mazeID int, version uint64, algorithm algorithms.Algorithm, index algorithms.Index) {
+	store.mu.Lock()
+	store.indexes[indexKey{mazeID: mazeID, algorithmID: algorithm.ID}] = versionedIndex{version: version, index: index}
+	store.mu.Unlock()
+}
+
+// update обновляет уже построенные индексы лабиринта до новой версии
+func (store *indexes) update(mazeID int, version uint64, board [][]bool) {
+	store.mu.Lock()
+	var stale []algorithms.Algorithm
+	for key := range store.indexes {
+		if key.mazeID != mazeID {
+			continue
+		}
+		if algorithm, found := algorithms.Get(key.algorithmID); found {
+			stale = append(stale, algorithm)
+		}
+	}
+	store.mu.Unlock()
+
+	for _, algorithm := range stale {
+		store.get(mazeID, version, algorithm, board)
+	}
+}
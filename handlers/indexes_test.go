@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	"algo/algorithms"
+	_ "algo/algorithms/hpa_star"
+	"algo/maze"
+)
+
+func TestIndexesUpdateOnMazeChange(t *testing.T) {
+	algorithm, found := algorithms.Get(6)
+	if !found || algorithm.Preprocess == nil {
+		t.Fatal("algorithm 6 must be registered with preprocessing")
+	}
+
+	board, err := maze.Generate(41, 41, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newIndexes()
+
+	index := store.get(1, maze.Version(board), algorithm, board)
+	if store.get(1, maze.Version(board), algorithm, board) != index {
+		t.Error("index must be reused for the same maze version")
+	}
+
+	board[1][1] = true
+	version := maze.Version(board)
+	store.update(1, version, board)
+	updated := store.get(1, version, algorithm, board)
+	if updated == index {
+		t.Fatal("index must be updated after maze change")
+	}
+
+	rebuilt := algorithm.Preprocess(board)
+	start, targets := [2]int{1, 0}, [][2]int{{39, 40}}
+	if got, want := updated.Solve(start[0], start[1], targets).Dist, rebuilt.Solve(start[0], start[1], targets).Dist; got != want {
+		t.Errorf("updated index found dist %d, rebuilt index %d", got, want)
+	}
+
+	store.update(2, version, board)
+	if len(store.indexes) != 1 {
+		t.Errorf("update must not build indexes for mazes without them, got %d indexes", len(store.indexes))
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/alt"
+	"algo/handlers/models"
+	"algo/maze"
+)
+
+// landmarkOptions возвращает параметры выбора ориентиров ALT из конфигурации лабиринта
+func landmarkOptions(entry maze.Entry) alt.Options {
+	options := alt.DefaultOptions
+	if entry.Landmarks.Count > 0 {
+		options.Count = entry.Landmarks.Count
+	}
+	// Стратегия проверена при загрузке конфигурации
+	if strategy, err := alt.ParseStrategy(entry.Landmarks.Strategy); err == nil {
+		options.Strategy = strategy
+	}
+	return options
+}
+
+// landmarks возвращает ориентиры ALT для версии лабиринта, выбирая их с параметрами из конфигурации лабиринта.
+// Ориентиры, выбранные с другими параметрами, например до перечитывания конфигурации, выбираются заново
+func (app *App) landmarks(entry maze.Entry, board [][]bool) *alt.Index {
+	algorithm, _ := algorithms.GetByName(alt.Name)
+	options := landmarkOptions(entry)
+	algorithm.Preprocess = func(board [][]bool) algorithms.Index {
+		return alt.Preprocess(board, options)
+	}
+
+	version := maze.Version(board)
+	index := app.indexes.get(entry.ID, version, algorithm, board).(*alt.Index)
+	if index.Options() != options {
+		index = alt.Preprocess(board, options)
+		app.indexes.put(entry.ID, version, algorithm, index)
+	}
+	return index
+}
+
+// preprocessMazes выбирает ориентиры ALT для всех лабиринтов каталога, чтобы первый запрос к лабиринту не ждал
+// их выбора. Лабиринт, который не удалось прочитать, пропускается: ошибку получит запрос к нему
+func (app *App) preprocessMazes(catalog *maze.Catalog) {
+	for _, entry := range catalog.All() {
+		board, err := entry.Load()
+		if err != nil {
+			continue
+		}
+		app.landmarks(entry, board)
+	}
+}
+
+func toLandmarksV2(index *alt.Index) models.LandmarksV2 {
+	landmarks := index.Landmarks()
+	output := models.LandmarksV2{
+		Strategy:       string(index.Options().Strategy),
+		Cells:          make([]models.Cell, len(landmarks)),
+		PreprocessTime: index.Elapsed().Round(time.Microsecond),
+	}
+	for i, landmark := range landmarks {
+		output.Cells[i] = models.Cell{Row: landmark[0], Col: landmark[1]}
+	}
+	return output
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"algo/config"
+	"algo/handlers/models"
+	"algo/maze"
+	"github.com/gorilla/mux"
+)
+
+func TestMazeLandmarks(t *testing.T) {
+	board, err := maze.Generate(21, 21, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "maze.txt")
+	if err = maze.SaveMaze(path, board, maze.FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.AppConfig{Mazes: []config.MazeConfig{{ID: 1, Name: "labyrinth", Path: path}}}
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := mux.NewRouter()
+	app.RegisterRoutes(router)
+
+	getLandmarks := func() models.LandmarksV2 {
+		t.Helper()
+		var output models.MazeOutputV2
+		if err := json.NewDecoder(serve(t, router, http.MethodGet, "/api/v2/mazes/1", "").Body).Decode(&output); err != nil {
+			t.Fatal(err)
+		}
+		return output.Landmarks
+	}
+
+	landmarks := getLandmarks()
+	if landmarks.Strategy != "avoid" || len(landmarks.Cells) != 8 || landmarks.PreprocessTime <= 0 {
+		t.Fatalf("landmarks = %+v, want 8 landmarks selected by avoid", landmarks)
+	}
+
+	// Ориентир, заложенный стеной, заменяется при изменении лабиринта
+	walled := landmarks.Cells[0]
+	body := fmt.Sprintf(`{"cells": [{"row": %d, "col": %d, "wall": true}]}`, walled.Row, walled.Col)
+	var patched models.MazeOutputV2
+	if err = json.NewDecoder(serve(t, router, http.MethodPatch, "/api/v2/mazes/1/cells", body).Body).Decode(&patched); err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(patched.Landmarks.Cells, walled) || len(patched.Landmarks.Cells) != 8 {
+		t.Errorf("landmarks after patch = %+v, walled landmark %+v", patched.Landmarks.Cells, walled)
+	}
+
+	// Перечитанная конфигурация с другими параметрами выбирает ориентиры заново
+	cfg.Mazes[0].Landmarks = config.LandmarksConfig{Count: 2, Strategy: "farthest"}
+	if err = app.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if landmarks = getLandmarks(); landmarks.Strategy != "farthest" || len(landmarks.Cells) != 2 {
+		t.Errorf("landmarks after reload = %+v, want 2 landmarks selected by farthest", landmarks)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"algo/apierror"
+	"algo/config"
+	"algo/handlers/models"
+	"algo/maze"
+	"algo/metrics"
+)
+
+// appState конфигурация и каталог лабиринтов, обработчик получает их один раз в начале запроса
+type appState struct {
+	cfg     config.AppConfig
+	catalog *maze.Catalog
+}
+
+// resolveMaze ищет лабиринт из запроса в каталоге
+func (state *appState) resolveMaze(ref models.MazeRef) (maze.Entry, error) {
+	if ref == "" {
+		return maze.Entry{}, apierror.NewInvalidError(apierror.CodeInvalidMazeID, "labirint_id is required")
+	}
+
+	entry, found := state.catalog.Resolve(string(ref))
+	if !found {
+		return maze.Entry{}, apierror.NewInvalidError(apierror.CodeInvalidMazeID, fmt.Sprintf("maze %q does not exist", ref))
+	}
+
+	return entry, nil
+}
+
+// updateMaze меняет клетки лабиринта на противоположные, если лабиринт можно изменять
+func (app *App) updateMaze(entry maze.Entry, board [][]bool, cells []models.Cell) ([][]bool, error) {
+	if entry.ReadOnly {
+		return nil, apierror.NewError(http.StatusForbidden, apierror.CodeMazeReadOnly, fmt.Sprintf("maze %q is read-only", entry.Name))
+	}
+
+	board, err := maze.UpdateMaze(entry.Path, entry.Format, board, cells)
+	if err != nil {
+		return nil, err
+	}
+	metrics.ObserveMazeUpdate(entry.ID, metrics.OperationUpdate)
+	app.mazeChanged(entry, board)
+
+	return board, nil
+}
+
+// restoreMaze возвращает лабиринт к исходной версии. Лабиринт только для чтения не может измениться,
+// поэтому для него возвращается текущая версия
+func (app *App) restoreMaze(entry maze.Entry) ([][]bool, error) {
+	if entry.ReadOnly {
+		return entry.Load()
+	}
+
+	if !entry.Restorable() {
+		return nil, apierror.NewError(http.StatusConflict, apierror.CodeMazeNotRestorable, fmt.Sprintf("maze %q has no original version", entry.Name))
+	}
+
+	board, err := maze.RestoreMaze(entry.Path, entry.Original, entry.Format)
+	if err != nil {
+		return nil, err
+	}
+	metrics.ObserveMazeUpdate(entry.ID, metrics.OperationRestore)
+	app.mazeChanged(entry, board)
+
+	return board, nil
+}
+
+// mazeChanged сбрасывает кэш результатов лабиринта и заранее строит поле расстояний до выходов, обновляет индексы
+// алгоритмов и ориентиры ALT для новой версии, чтобы первый запрос к измененному лабиринту не ждал их построения
+func (app *App) mazeChanged(entry maze.Entry, board [][]bool) {
+	app.invalidateMaze(entry.ID)
+	version := maze.Version(board)
+	app.exits.get(entry.ID, version, board)
+	app.indexes.update(entry.ID, version, board)
+	app.landmarks(entry, board)
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Cell задает клетку доски индексами матрицы лабиринта: номером строки и номером столбца.
+// Внутри сервиса и в API v2 используются только клетки
+type Cell struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// Point задает клетку доски в API v1 парой координат x и y.
+// Какая из координат соответствует строке, а какая столбцу, определяется параметром запроса coords
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Coords задает соответствие координат Point строкам и столбцам доски
+type Coords string
+
+const (
+	// CoordsXY x — номер столбца, y — номер строки, как на экране. Используется по умолчанию
+	CoordsXY Coords = "xy"
+	// CoordsRowCol x — номер строки, y — номер столбца, как индексы матрицы
+	CoordsRowCol Coords = "row_col"
+)
+
+// Validate проверяет, что система координат известна, пустое значение соответствует CoordsXY
+func (c Coords) Validate() error {
+	switch c {
+	case "", CoordsXY, CoordsRowCol:
+		return nil
+	default:
+		return fmt.Errorf("invalid coords %q, expected %q or %q", c, CoordsXY, CoordsRowCol)
+	}
+}
+
+// Cell переводит точку в клетку доски
+func (c Coords) Cell(point Point) Cell {
+	if c == CoordsRowCol {
+		return Cell{Row: point.X, Col: point.Y}
+	}
+	return Cell{Row: point.Y, Col: point.X}
+}
+
+// Cells переводит список точек в список клеток доски
+func (c Coords) Cells(points []Point) []Cell {
+	cells := make([]Cell, len(points))
+	for i, point := range points {
+		cells[i] = c.Cell(point)
+	}
+	return cells
+}
+
+// Point переводит клетку доски в точку
+func (c Coords) Point(cell Cell) Point {
+	if c == CoordsRowCol {
+		return Point{X: cell.Row, Y: cell.Col}
+	}
+	return Point{X: cell.Col, Y: cell.Row}
+}
@@ -0,0 +1,39 @@
+package models
+
+// Значения поля status в ответах /healthz и /readyz
+const (
+	StatusOK       = "ok"
+	StatusNotReady = "not_ready"
+)
+
+type HealthOutput struct {
+	Status string `json:"status"`
+}
+
+type ReadyOutput struct {
+	Status string       `json:"status"`
+	Checks []ReadyCheck `json:"checks"`
+}
+
+// ReadyCheck результат одной проверки готовности, Error заполняется только для неуспешной проверки
+type ReadyCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type VersionOutput struct {
+	Commit     string          `json:"commit"`
+	BuildTime  string          `json:"build_time"`
+	Modified   bool            `json:"modified"`
+	GoVersion  string          `json:"go_version"`
+	Algorithms []AlgorithmInfo `json:"algorithms"`
+}
+
+type AlgorithmInfo struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	AnyAngle    bool   `json:"any_angle"`
+	Approximate bool   `json:"approximate"`
+}
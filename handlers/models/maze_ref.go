@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// MazeRef ссылается на лабиринт из каталога по идентификатору или по имени.
+// В JSON задается числом (идентификатор) или строкой (имя или идентификатор)
+type MazeRef string
+
+// NewMazeRef возвращает ссылку на лабиринт по идентификатору
+func NewMazeRef(id int) MazeRef {
+	return MazeRef(strconv.Itoa(id))
+}
+
+func (ref *MazeRef) UnmarshalJSON(data []byte) error {
+	var id int
+	if err := json.Unmarshal(data, &id); err == nil {
+		*ref = NewMazeRef(id)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.New("maze reference must be an integer id or a string name")
+	}
+	*ref = MazeRef(name)
+	return nil
+}
+
+func (ref MazeRef) MarshalJSON() ([]byte, error) {
+	if id, err := strconv.Atoi(string(ref)); err == nil {
+		return json.Marshal(id)
+	}
+	return json.Marshal(string(ref))
+}
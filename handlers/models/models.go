@@ -4,21 +4,90 @@ import (
 	"fmt"
 	"time"
 
+	"algo/algorithms"
+	"algo/algorithms/heuristics"
+	"algo/apierror"
 	"algo/config"
-	"github.com/pkg/errors"
 )
 
+// Порядок обхода промежуточных клеток
+const (
+	ViaFixed   = "fixed"   // В порядке перечисления
+	ViaOptimal = "optimal" // В порядке, при котором путь кратчайший
+)
+
+// MaxVia наибольшее количество промежуточных клеток в одном запросе
+const MaxVia = 50
+
 type SolveMazeInput struct {
-	MazeID      int     `json:"labirint_id"`
+	MazeID      MazeRef `json:"labirint_id"`
 	AlgorithmID int     `json:"algorithm_id"`
 	Start       Point   `json:"start"`
 	End         []Point `json:"end,omitempty"`
+	Coords      Coords  `json:"coords,omitempty"`
+
+	HeuristicWeight float64 `json:"heuristic_weight,omitempty"` // Вес эвристики, 0 и 1 означают поиск кратчайшего пути
+	Heuristic       string  `json:"heuristic,omitempty"`        // Эвристика, пустая строка — эвристика модели перемещения по умолчанию
+	Movement        string  `json:"movement,omitempty"`         // Модель перемещения grid4 или grid8, пустая строка — grid4
+
+	Via      []Point `json:"via,omitempty"`       // Промежуточные клетки, через которые должен пройти путь
+	ViaOrder string  `json:"via_order,omitempty"` // fixed или optimal, пустая строка — fixed
 }
 
 type SolveMazeOutput struct {
-	Path          []Tranzition  `json:"path"`
-	Dist          int           `json:"dist"`
-	ExecutionTime time.Duration `json:"time"`
+	Path          []Tranzition   `json:"path"`
+	Dist          int            `json:"dist"`
+	ExecutionTime time.Duration  `json:"time"`
+	Stats         map[string]int `json:"stats,omitempty"`
+	Solver        string         `json:"solver,omitempty"` // Алгоритм, который построил путь: имя из algorithm_id или exit-field
+
+	SuboptimalityBound float64 `json:"suboptimality_bound,omitempty"` // Путь не длиннее кратчайшего, умноженного на это значение
+	VisitOrder         []int   `json:"visit_order,omitempty"`         // Номера промежуточных клеток из via в порядке обхода
+}
+
+type BatchSolveMazeInput struct {
+	MazeID  MazeRef      `json:"labirint_id"`
+	Queries []BatchQuery `json:"queries"`
+	Coords  Coords       `json:"coords,omitempty"`
+}
+
+type BatchQuery struct {
+	AlgorithmID int     `json:"algorithm_id"`
+	Start       Point   `json:"start"`
+	End         []Point `json:"end,omitempty"`
+}
+
+type BatchSolveMazeOutput struct {
+	Results []BatchResult `json:"results"`
+}
+
+type BatchResult struct {
+	Result *SolveMazeOutput    `json:"result,omitempty"`
+	Error  *apierror.ErrorBody `json:"error,omitempty"`
+}
+
+type CompareInput struct {
+	MazeID MazeRef `json:"labirint_id"`
+	Start  Point   `json:"start"`
+	End    []Point `json:"end,omitempty"`
+	Coords Coords  `json:"coords,omitempty"`
+}
+
+type CompareOutput struct {
+	OptimalDist int             `json:"optimal_dist"`
+	Results     []CompareResult `json:"results"`
+}
+
+type CompareResult struct {
+	AlgorithmID   int            `json:"algorithm_id"`
+	Name          string         `json:"name"`
+	Found         bool           `json:"found"`
+	Dist          int            `json:"dist"`
+	PathNodes     int            `json:"path_nodes"`
+	Expanded      int            `json:"expanded"`
+	ExecutionTime time.Duration  `json:"time"`
+	Optimal       bool           `json:"optimal"`
+	Stats         map[string]int `json:"stats,omitempty"`
 }
 
 type Tranzition struct {
@@ -26,14 +95,10 @@ type Tranzition struct {
 	End   Point `json:"end"`
 }
 
-type Point struct {
-	Y int `json:"x"`
-	X int `json:"y"`
-}
-
 type UpdateMazeInput struct {
-	MazeID int     `json:"labirint_id"`
+	MazeID MazeRef `json:"labirint_id"`
 	Points []Point `json:"points"`
+	Coords Coords  `json:"coords,omitempty"`
 }
 
 type UpdateMazeOutput struct {
@@ -41,7 +106,7 @@ type UpdateMazeOutput struct {
 }
 
 type GetMazeInput struct {
-	MazeID int `json:"labirint_id"`
+	MazeID MazeRef `json:"labirint_id"`
 }
 
 type GetMazeOutput struct {
@@ -49,72 +114,179 @@ type GetMazeOutput struct {
 }
 
 type RestoreMazeInput struct {
-	MazeID int `json:"labirint_id"`
+	MazeID MazeRef `json:"labirint_id"`
 }
 
 type RestoreMazeOutput struct {
 	Map [][]int `json:"labirint"`
 }
 
-func validateMazeID(mazeID int, cfg config.AppConfig) bool {
-	return 0 < mazeID && mazeID <= cfg.MazeCount
+func validateAlgorithmID(algorithmID int) bool {
+	_, found := algorithms.Get(algorithmID)
+	return found
 }
 
-func validateAlgorithmID(algorithmID int, cfg config.AppConfig) bool {
-	return 0 < algorithmID && algorithmID <= cfg.AlgorithmCount
+func validateCell(cell Cell, rows int, cols int) bool {
+	return 0 <= cell.Row && cell.Row < rows && 0 <= cell.Col && cell.Col < cols
 }
 
-func validatePoint(point Point, n int, m int) bool {
-	return 0 <= point.X && point.X < m && 0 <= point.Y && point.Y < n
+func validateAlgorithm(algorithmID int) error {
+	if !validateAlgorithmID(algorithmID) {
+		return apierror.NewInvalidError(apierror.CodeInvalidAlgorithmID, fmt.Sprintf("algorithm %d does not exist", algorithmID))
+	}
+
+	return nil
 }
 
-func (req *SolveMazeInput) Validate(cfg config.AppConfig, n int, m int) error {
-	if !validateMazeID(req.MazeID, cfg) {
-		return errors.New("invalid labirint_id")
+// validateSearchOptions проверяет вес, эвристику и модель перемещения. Эвристика должна быть допустимой для выбранной
+// модели перемещения или, если модель не выбрана, для модели алгоритма, иначе A* может вернуть не кратчайший путь
+func validateSearchOptions(algorithmID int, weight float64, heuristic string, movementName string) error {
+	algorithm, _ := algorithms.Get(algorithmID)
+
+	movement := algorithm.Movement
+	if movementName != "" {
+		selected, found := heuristics.GetMovement(movementName)
+		if !found {
+			return apierror.NewInvalidError(apierror.CodeInvalidMovement, fmt.Sprintf("movement %q does not exist", movementName))
+		}
+		if !algorithm.Movements {
+			return apierror.NewInvalidError(apierror.CodeInvalidMovement, fmt.Sprintf("algorithm %d does not support movement", algorithmID))
+		}
+		movement = selected
 	}
 
-	if !validateAlgorithmID(req.AlgorithmID, cfg) {
-		return errors.New("invalid algorithm_id")
+	if weight != 0 && weight != 1 {
+		if weight < 1 {
+			return apierror.NewInvalidError(apierror.CodeInvalidHeuristicWeight, fmt.Sprintf("heuristic_weight must be at least 1, got %g", weight))
+		}
+		if !algorithm.Weighted {
+			return apierror.NewInvalidError(apierror.CodeInvalidHeuristicWeight, fmt.Sprintf("algorithm %d does not support heuristic_weight", algorithmID))
+		}
 	}
 
-	if !validatePoint(req.Start, n, m) {
-		return errors.New("invalid start point")
+	if heuristic != "" {
+		h, found := heuristics.Get(heuristic)
+		if !found {
+			return apierror.NewInvalidError(apierror.CodeInvalidHeuristic, fmt.Sprintf("heuristic %q does not exist", heuristic))
+		}
+		if !algorithm.Heuristics {
+			return apierror.NewInvalidError(apierror.CodeInvalidHeuristic, fmt.Sprintf("algorithm %d does not support heuristic", algorithmID))
+		}
+		if err := heuristics.Check(h, movement); err != nil {
+			return apierror.NewInvalidError(apierror.CodeInvalidHeuristic, err.Error())
+		}
 	}
 
-	for i, end := range req.End {
-		if !validatePoint(end, n, m) {
-			return fmt.Errorf("invalid end point at index %d", i)
+	return nil
+}
+
+// validateVia проверяет промежуточные клетки и порядок их обхода. Порядок обхода подбирается по расстояниям
+// для ходов по сторонам, поэтому промежуточные клетки допустимы только с моделью перемещения grid4
+func validateVia(via []Cell, order string, movement string, rows int, cols int) error {
+	if order != "" && order != ViaFixed && order != ViaOptimal {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("via_order must be %s or %s, got %q", ViaFixed, ViaOptimal, order))
+	}
+
+	if len(via) > 0 && movement != "" && movement != heuristics.Grid4.Name {
+		return apierror.NewInvalidError(apierror.CodeInvalidMovement, fmt.Sprintf("via is supported only with %q movement, got %q", heuristics.Grid4.Name, movement))
+	}
+
+	if len(via) > MaxVia {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("too many via cells: %d > %d", len(via), MaxVia))
+	}
+
+	for i, cell := range via {
+		if !validateCell(cell, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeViaOutOfBounds, i, fmt.Sprintf("via cell (row=%d, col=%d) is out of %dx%d maze", cell.Row, cell.Col, rows, cols))
 		}
 	}
 
 	return nil
 }
 
-func (req *UpdateMazeInput) Validate(cfg config.AppConfig, n int, m int) error {
-	if !validateMazeID(req.MazeID, cfg) {
-		return errors.New("invalid labirint_id")
+func validateCoords(coords Coords) error {
+	if err := coords.Validate(); err != nil {
+		return apierror.NewInvalidError(apierror.CodeInvalidCoords, err.Error())
 	}
 
-	for i, point := range req.Points {
-		if !validatePoint(point, n, m) {
-			return fmt.Errorf("invalid point at index %d", i)
+	return nil
+}
+
+func validateEndpoints(start Cell, end []Cell, rows int, cols int) error {
+	if !validateCell(start, rows, cols) {
+		return apierror.NewInvalidError(apierror.CodeStartOutOfBounds, fmt.Sprintf("start cell (row=%d, col=%d) is out of %dx%d maze", start.Row, start.Col, rows, cols))
+	}
+
+	for i, cell := range end {
+		if !validateCell(cell, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeEndOutOfBounds, i, fmt.Sprintf("end cell (row=%d, col=%d) is out of %dx%d maze", cell.Row, cell.Col, rows, cols))
 		}
 	}
 
 	return nil
 }
 
-func (req *GetMazeInput) Validate(cfg config.AppConfig) error {
-	if !validateMazeID(req.MazeID, cfg) {
-		return errors.New("invalid labirint_id")
+func (req *SolveMazeInput) Validate(rows int, cols int) error {
+	if err := validateAlgorithm(req.AlgorithmID); err != nil {
+		return err
+	}
+
+	if err := validateSearchOptions(req.AlgorithmID, req.HeuristicWeight, req.Heuristic, req.Movement); err != nil {
+		return err
+	}
+
+	if err := validateCoords(req.Coords); err != nil {
+		return err
+	}
+
+	if err := validateVia(req.Coords.Cells(req.Via), req.ViaOrder, req.Movement, rows, cols); err != nil {
+		return err
+	}
+
+	return validateEndpoints(req.Coords.Cell(req.Start), req.Coords.Cells(req.End), rows, cols)
+}
+
+func (req *BatchSolveMazeInput) Validate(cfg config.AppConfig) error {
+	if err := validateCoords(req.Coords); err != nil {
+		return err
+	}
+
+	if len(req.Queries) == 0 {
+		return apierror.NewInvalidError(apierror.CodeEmptyQueries, "queries must not be empty")
+	}
+
+	if cfg.BatchMaxQueries > 0 && len(req.Queries) > cfg.BatchMaxQueries {
+		return apierror.NewInvalidError(apierror.CodeTooManyQueries, fmt.Sprintf("too many queries: %d > %d", len(req.Queries), cfg.BatchMaxQueries))
 	}
 
 	return nil
 }
 
-func (req *RestoreMazeInput) Validate(cfg config.AppConfig) error {
-	if !validateMazeID(req.MazeID, cfg) {
-		return errors.New("invalid labirint_id")
+func (query *BatchQuery) Validate(coords Coords, rows int, cols int) error {
+	if err := validateAlgorithm(query.AlgorithmID); err != nil {
+		return err
+	}
+
+	return validateEndpoints(coords.Cell(query.Start), coords.Cells(query.End), rows, cols)
+}
+
+func (req *CompareInput) Validate(rows int, cols int) error {
+	if err := validateCoords(req.Coords); err != nil {
+		return err
+	}
+
+	return validateEndpoints(req.Coords.Cell(req.Start), req.Coords.Cells(req.End), rows, cols)
+}
+
+func (req *UpdateMazeInput) Validate(rows int, cols int) error {
+	if err := validateCoords(req.Coords); err != nil {
+		return err
+	}
+
+	for i, point := range req.Points {
+		if cell := req.Coords.Cell(point); !validateCell(cell, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeCellOutOfBounds, i, fmt.Sprintf("cell (row=%d, col=%d) is out of %dx%d maze", cell.Row, cell.Col, rows, cols))
+		}
 	}
 
 	return nil
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "algo/algorithms/a_star"
+	"algo/config"
+)
+
+var testConfig = config.AppConfig{BatchMaxQueries: 1000}
+
+// inBounds проверяет, что клетку можно использовать как индекс доски из rows строк и cols столбцов
+func inBounds(cell Cell, rows, cols int) bool {
+	return cell.Row >= 0 && cell.Row < rows && cell.Col >= 0 && cell.Col < cols
+}
+
+func TestCoords(t *testing.T) {
+	tests := []struct {
+		coords Coords
+		point  Point
+		cell   Cell
+	}{
+		{coords: "", point: Point{X: 1, Y: 0}, cell: Cell{Row: 0, Col: 1}},
+		{coords: CoordsXY, point: Point{X: 40, Y: 39}, cell: Cell{Row: 39, Col: 40}},
+		{coords: CoordsRowCol, point: Point{X: 1, Y: 0}, cell: Cell{Row: 1, Col: 0}},
+	}
+
+	for _, test := range tests {
+		if err := test.coords.Validate(); err != nil {
+			t.Fatalf("coords %q: %v", test.coords, err)
+		}
+		if got := test.coords.Cell(test.point); got != test.cell {
+			t.Errorf("coords %q: Cell(%+v) = %+v, want %+v", test.coords, test.point, got, test.cell)
+		}
+		if got := test.coords.Point(test.cell); got != test.point {
+			t.Errorf("coords %q: Point(%+v) = %+v, want %+v", test.coords, test.cell, got, test.point)
+		}
+	}
+
+	if err := Coords("yx").Validate(); err == nil {
+		t.Error("expected error for unknown coords")
+	}
+}
+
+func TestMazeRef(t *testing.T) {
+	tests := []struct {
+		json string
+		ref  MazeRef
+	}{
+		{json: `1`, ref: "1"},
+		{json: `"1"`, ref: "1"},
+		{json: `"many-targets"`, ref: "many-targets"},
+	}
+
+	for _, test := range tests {
+		var ref MazeRef
+		if err := json.Unmarshal([]byte(test.json), &ref); err != nil {
+			t.Fatalf("%s: %v", test.json, err)
+		}
+		if ref != test.ref {
+			t.Errorf("%s: got %q, want %q", test.json, ref, test.ref)
+		}
+	}
+
+	for _, data := range []string{`1.5`, `true`, `{}`, `[1]`} {
+		var ref MazeRef
+		if err := json.Unmarshal([]byte(data), &ref); err == nil {
+			t.Errorf("%s: expected error, got %q", data, ref)
+		}
+	}
+
+	for ref, want := range map[MazeRef]string{"2": `2`, "labyrinth": `"labyrinth"`} {
+		data, err := json.Marshal(ref)
+		if err != nil || string(data) != want {
+			t.Errorf("Marshal(%q) = %s, %v, want %s", ref, data, err, want)
+		}
+	}
+}
+
+func FuzzSolveMazeInputValidate(f *testing.F) {
+	f.Add([]byte(`{"labirint_id": 2, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "end": [{"x": 39, "y": 40}]}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": -1, "y": 0}}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 3, "algorithm_id": 9, "start": {"x": 5, "y": 5}}`), 3, 7)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 0, "y": 0}, "end": [{"x": 0, "y": 3}]}`), 3, 3)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "coords": "row_col"}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 6, "y": 0}, "coords": "row_col"}`), 5, 10)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "heuristic_weight": 2.5}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "heuristic_weight": 0.5}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "heuristic": "octile"}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 3, "start": {"x": 1, "y": 0}, "heuristic": "zero"}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 1, "y": 0}, "via": [{"x": 5, "y": 5}, {"x": 41, "y": 0}], "via_order": "optimal"}`), 41, 41)
+
+	f.Fuzz(func(t *testing.T, data []byte, rows, cols int) {
+		var req SolveMazeInput
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		if err := req.Validate(rows, cols); err != nil {
+			return
+		}
+
+		if !inBounds(req.Coords.Cell(req.Start), rows, cols) {
+			t.Fatalf("accepted start %+v for %dx%d board", req.Start, rows, cols)
+		}
+		for _, end := range req.End {
+			if !inBounds(req.Coords.Cell(end), rows, cols) {
+				t.Fatalf("accepted end %+v for %dx%d board", end, rows, cols)
+			}
+		}
+		if req.HeuristicWeight != 0 && req.HeuristicWeight < 1 {
+			t.Fatalf("accepted heuristic_weight %g", req.HeuristicWeight)
+		}
+	})
+}
+
+func FuzzUpdateMazeInputValidate(f *testing.F) {
+	f.Add([]byte(`{"labirint_id": 2, "points": [{"x": 0, "y": 40}, {"x": 1, "y": 40}]}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 1, "points": [{"x": 41, "y": 0}]}`), 41, 41)
+
+	f.Fuzz(func(t *testing.T, data []byte, rows, cols int) {
+		var req UpdateMazeInput
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		if err := req.Validate(rows, cols); err != nil {
+			return
+		}
+
+		for _, point := range req.Points {
+			if !inBounds(req.Coords.Cell(point), rows, cols) {
+				t.Fatalf("accepted point %+v for %dx%d board", point, rows, cols)
+			}
+		}
+	})
+}
+
+func FuzzBatchSolveMazeInputValidate(f *testing.F) {
+	f.Add([]byte(`{"labirint_id": 2, "queries": [{"algorithm_id": 1, "start": {"x": 0, "y": 1}}]}`), 41, 41)
+	f.Add([]byte(`{"labirint_id": 2, "queries": []}`), 41, 41)
+
+	f.Fuzz(func(t *testing.T, data []byte, rows, cols int) {
+		var req BatchSolveMazeInput
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		if err := req.Validate(testConfig); err != nil {
+			return
+		}
+		if len(req.Queries) == 0 {
+			t.Fatal("accepted empty batch")
+		}
+
+		for _, query := range req.Queries {
+			if err := query.Validate(req.Coords, rows, cols); err != nil {
+				continue
+			}
+			if !inBounds(req.Coords.Cell(query.Start), rows, cols) {
+				t.Fatalf("accepted start %+v for %dx%d board", query.Start, rows, cols)
+			}
+			for _, end := range query.End {
+				if !inBounds(req.Coords.Cell(end), rows, cols) {
+					t.Fatalf("accepted end %+v for %dx%d board", end, rows, cols)
+				}
+			}
+		}
+	})
+}
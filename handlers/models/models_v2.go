@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"algo/apierror"
+)
+
+type MazeSummaryV2 struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Format      string      `json:"format"`
+	ReadOnly    bool        `json:"read_only"`
+	Restorable  bool        `json:"restorable"`
+	Rows        int         `json:"rows"`
+	Cols        int         `json:"cols"`
+	Landmarks   LandmarksV2 `json:"landmarks"`
+}
+
+type ListMazesOutputV2 struct {
+	Mazes []MazeSummaryV2 `json:"mazes"`
+}
+
+type MazeOutputV2 struct {
+	ID        int         `json:"id"`
+	Name      string      `json:"name"`
+	Rows      int         `json:"rows"`
+	Cols      int         `json:"cols"`
+	Cells     [][]int     `json:"cells"`
+	Landmarks LandmarksV2 `json:"landmarks"`
+}
+
+// LandmarksV2 ориентиры ALT, выбранные для текущей версии лабиринта
+type LandmarksV2 struct {
+	Strategy       string        `json:"strategy"`
+	Cells          []Cell        `json:"cells"`
+	PreprocessTime time.Duration `json:"preprocess_time"` // Время выбора ориентиров и расчета таблиц расстояний
+}
+
+// ExitDistanceOutputV2 поле расстояний до ближайшего выхода, -1 — стена или клетка, из которой нет выхода
+type ExitDistanceOutputV2 struct {
+	ID   int     `json:"id"`
+	Name string  `json:"name"`
+	Rows int     `json:"rows"`
+	Cols int     `json:"cols"`
+	Max  int     `json:"max"`
+	Dist [][]int `json:"dist"`
+}
+
+type CellPatchV2 struct {
+	Row  int  `json:"row"`
+	Col  int  `json:"col"`
+	Wall bool `json:"wall"`
+}
+
+type PatchCellsInputV2 struct {
+	Cells []CellPatchV2 `json:"cells"`
+}
+
+type FindPathInputV2 struct {
+	AlgorithmID int    `json:"algorithm_id"`
+	Start       Cell   `json:"start"`
+	End         []Cell `json:"end,omitempty"`
+
+	HeuristicWeight float64 `json:"heuristic_weight,omitempty"` // Вес эвристики, 0 и 1 означают поиск кратчайшего пути
+	Heuristic       string  `json:"heuristic,omitempty"`        // Эвристика, пустая строка — эвристика модели перемещения по умолчанию
+	Movement        string  `json:"movement,omitempty"`         // Модель перемещения grid4 или grid8, пустая строка — grid4
+
+	Via      []Cell `json:"via,omitempty"`       // Промежуточные клетки, через которые должен пройти путь
+	ViaOrder string `json:"via_order,omitempty"` // fixed или optimal, пустая строка — fixed
+}
+
+type FindPathOutputV2 struct {
+	Path          []Cell         `json:"path"`
+	Dist          int            `json:"dist"`
+	ExecutionTime time.Duration  `json:"time"`
+	Stats         map[string]int `json:"stats,omitempty"`
+	Solver        string         `json:"solver,omitempty"` // Алгоритм, который построил путь: имя из algorithm_id или exit-field
+
+	SuboptimalityBound float64 `json:"suboptimality_bound,omitempty"` // Путь не длиннее кратчайшего, умноженного на это значение
+	VisitOrder         []int   `json:"visit_order,omitempty"`         // Номера промежуточных клеток из via в порядке обхода
+}
+
+// MaxAnytimeBudget наибольшее время, которое поиск с улучшением пути может потратить на один запрос
+const MaxAnytimeBudget = 5 * time.Second
+
+type AnytimePathInputV2 struct {
+	Start         Cell    `json:"start"`
+	End           []Cell  `json:"end,omitempty"`
+	BudgetMs      int     `json:"budget_ms,omitempty"`      // Время на улучшение пути в миллисекундах
+	InitialWeight float64 `json:"initial_weight,omitempty"` // Вес эвристики первого решения
+}
+
+type AnytimeSolutionV2 struct {
+	Path          []Cell        `json:"path"`
+	Dist          int           `json:"dist"`
+	Weight        float64       `json:"weight"`
+	Bound         float64       `json:"bound"`
+	Expanded      int           `json:"expanded"`
+	ExecutionTime time.Duration `json:"time"`
+}
+
+type AnytimePathOutputV2 struct {
+	Solutions []AnytimeSolutionV2 `json:"solutions"`
+}
+
+// Способы поиска альтернативных путей
+const (
+	AlternativesShortest = "shortest" // k кратчайших путей без повторяющихся клеток (алгоритм Йена)
+	AlternativesDiverse  = "diverse"  // Непохожие пути методом штрафов
+)
+
+// Количество альтернативных путей по умолчанию и наибольшее количество в одном запросе
+const (
+	DefaultAlternatives = 3
+	MaxAlternatives     = 10
+)
+
+// AlternativesBudget наибольшее время поиска альтернативных путей в одном запросе, меньше таймаута записи ответа
+const AlternativesBudget = 5 * time.Second
+
+type AlternativePathsInputV2 struct {
+	Start   Cell    `json:"start"`
+	End     []Cell  `json:"end,omitempty"`
+	K       int     `json:"k,omitempty"`       // Количество путей, 0 — DefaultAlternatives
+	Mode    string  `json:"mode,omitempty"`    // shortest или diverse, пустая строка — shortest
+	Penalty float64 `json:"penalty,omitempty"` // Штраф за повторное использование клетки в режиме diverse, 0 — по умолчанию
+}
+
+type AlternativePathV2 struct {
+	Path []Cell `json:"path"`
+	Dist int    `json:"dist"`
+}
+
+type AlternativePathsOutputV2 struct {
+	Paths         []AlternativePathV2 `json:"paths"`
+	Expanded      int                 `json:"expanded"`
+	ExecutionTime time.Duration       `json:"time"`
+	Truncated     bool                `json:"truncated,omitempty"` // Поиск остановлен по времени, путей может быть меньше k
+}
+
+// MultiAgentBudget наибольшее время поиска путей агентов в одном запросе, меньше таймаута записи ответа
+const MultiAgentBudget = 5 * time.Second
+
+// MaxAgents наибольшее количество агентов в одном запросе
+const MaxAgents = 16
+
+type AgentV2 struct {
+	Start Cell `json:"start"`
+	Goal  Cell `json:"goal"`
+}
+
+type MultiAgentInputV2 struct {
+	Agents []AgentV2 `json:"agents"`
+}
+
+type AgentPathV2 struct {
+	Path []Cell `json:"path"` // Клетка агента в каждый момент времени, начиная с 0
+	Cost int    `json:"cost"` // Момент, когда агент окончательно пришел в цель
+}
+
+type MultiAgentOutputV2 struct {
+	Paths         []AgentPathV2 `json:"paths"`
+	Makespan      int           `json:"makespan"`     // Момент прихода последнего агента, -1 — решение не найдено
+	SumOfCosts    int           `json:"sum_of_costs"` // Сумма стоимостей путей, -1 — решение не найдено
+	Expanded      int           `json:"expanded"`
+	Nodes         int           `json:"nodes"`                   // Количество раскрытых узлов дерева ограничений
+	LimitReached  bool          `json:"limit_reached,omitempty"` // Поиск остановлен по ограничению на количество узлов или по времени
+	ExecutionTime time.Duration `json:"time"`
+}
+
+func (req *PatchCellsInputV2) Validate(rows int, cols int) error {
+	if len(req.Cells) == 0 {
+		return apierror.NewInvalidError(apierror.CodeEmptyCells, "cells must not be empty")
+	}
+
+	for i, patch := range req.Cells {
+		if !validateCell(Cell{Row: patch.Row, Col: patch.Col}, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeCellOutOfBounds, i, fmt.Sprintf("cell (row=%d, col=%d) is out of %dx%d maze", patch.Row, patch.Col, rows, cols))
+		}
+	}
+
+	return nil
+}
+
+func (req *FindPathInputV2) Validate(rows int, cols int) error {
+	if err := validateAlgorithm(req.AlgorithmID); err != nil {
+		return err
+	}
+
+	if err := validateSearchOptions(req.AlgorithmID, req.HeuristicWeight, req.Heuristic, req.Movement); err != nil {
+		return err
+	}
+
+	if err := validateVia(req.Via, req.ViaOrder, req.Movement, rows, cols); err != nil {
+		return err
+	}
+
+	return validateEndpoints(req.Start, req.End, rows, cols)
+}
+
+func (req *AnytimePathInputV2) Validate(rows int, cols int) error {
+	if req.BudgetMs < 0 || time.Duration(req.BudgetMs)*time.Millisecond > MaxAnytimeBudget {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("budget_ms must be from 0 to %d, got %d", MaxAnytimeBudget.Milliseconds(), req.BudgetMs))
+	}
+	if req.InitialWeight != 0 && req.InitialWeight < 1 {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("initial_weight must be at least 1, got %g", req.InitialWeight))
+	}
+
+	return validateEndpoints(req.Start, req.End, rows, cols)
+}
+
+func (req *AlternativePathsInputV2) Validate(rows int, cols int) error {
+	if req.K < 0 || req.K > MaxAlternatives {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("k must be from 0 to %d, got %d", MaxAlternatives, req.K))
+	}
+	if req.Mode != "" && req.Mode != AlternativesShortest && req.Mode != AlternativesDiverse {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("mode must be %q or %q, got %q", AlternativesShortest, AlternativesDiverse, req.Mode))
+	}
+	if req.Penalty < 0 || (req.Penalty > 0 && req.Mode != AlternativesDiverse) {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("penalty must be positive and is allowed only in %q mode, got %g", AlternativesDiverse, req.Penalty))
+	}
+
+	return validateEndpoints(req.Start, req.End, rows, cols)
+}
+
+func (req *MultiAgentInputV2) Validate(rows int, cols int) error {
+	if len(req.Agents) == 0 {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, "agents must not be empty")
+	}
+	if len(req.Agents) > MaxAgents {
+		return apierror.NewInvalidError(apierror.CodeInvalidRequest, fmt.Sprintf("too many agents: %d > %d", len(req.Agents), MaxAgents))
+	}
+
+	starts, goals := make(map[Cell]bool, len(req.Agents)), make(map[Cell]bool, len(req.Agents))
+	for i, agent := range req.Agents {
+		if !validateCell(agent.Start, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeStartOutOfBounds, i, fmt.Sprintf("start cell (row=%d, col=%d) is out of %dx%d maze", agent.Start.Row, agent.Start.Col, rows, cols))
+		}
+		if !validateCell(agent.Goal, rows, cols) {
+			return apierror.NewInvalidIndexError(apierror.CodeEndOutOfBounds, i, fmt.Sprintf("goal cell (row=%d, col=%d) is out of %dx%d maze", agent.Goal.Row, agent.Goal.Col, rows, cols))
+		}
+		if starts[agent.Start] {
+			return apierror.NewInvalidIndexError(apierror.CodeInvalidRequest, i, fmt.Sprintf("start cell (row=%d, col=%d) is shared with another agent", agent.Start.Row, agent.Start.Col))
+		}
+		if goals[agent.Goal] {
+			return apierror.NewInvalidIndexError(apierror.CodeInvalidRequest, i, fmt.Sprintf("goal cell (row=%d, col=%d) is shared with another agent", agent.Goal.Row, agent.Goal.Col))
+		}
+		starts[agent.Start], goals[agent.Goal] = true, true
+	}
+
+	return nil
+}
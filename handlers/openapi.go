@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"io/fs"
+	"net/http"
+
+	"algo/apierror"
+	"algo/handlers/docs"
+	"algo/utils"
+	"github.com/gorilla/mux"
+)
+
+// OpenAPIHandler отдает спецификацию OpenAPI
+func (app *App) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	serveDocsFile(w, r, docs.SpecFile)
+}
+
+// DocsHandler отдает страницу документации
+func (app *App) DocsHandler(w http.ResponseWriter, r *http.Request) {
+	serveDocsFile(w, r, docs.IndexFile)
+}
+
+// DocsAssetHandler отдает скрипт и стили страницы документации
+func (app *App) DocsAssetHandler(w http.ResponseWriter, r *http.Request) {
+	serveDocsFile(w, r, mux.Vars(r)["file"])
+}
+
+func serveDocsFile(w http.ResponseWriter, r *http.Request, name string) {
+	if _, err := fs.Stat(docs.FS, name); err != nil {
+		utils.WriteErrorBody(r.Context(), w, http.StatusNotFound, apierror.ErrorBody{
+			Code:    apierror.CodeNotFound,
+			Message: "file " + name + " not found",
+		})
+		return
+	}
+
+	// Content-Type определяется по расширению файла, значение по умолчанию выставляет middleware
+	w.Header().Del("Content-Type")
+	http.ServeFileFS(w, r, docs.FS, name)
+}
@@ -0,0 +1,499 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"algo/apierror"
+	"algo/config"
+	"algo/handlers/docs"
+	"algo/handlers/models"
+	"github.com/gorilla/mux"
+)
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 string                    `json:"type"`
+	Nullable             bool                      `json:"nullable"`
+	Items                *openAPISchema            `json:"items"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	Required             []string                  `json:"required"`
+	Enum                 []string                  `json:"enum"`
+	OneOf                []*openAPISchema          `json:"oneOf"`
+}
+
+type openAPIParameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type openAPIMedia struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Ref     string                  `json:"$ref"`
+	Content map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Parameters  []openAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]openAPIMedia `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPISpec struct {
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]openAPIParameter `json:"parameters"`
+		Responses  map[string]openAPIResponse  `json:"responses"`
+		Schemas    map[string]*openAPISchema   `json:"schemas"`
+	} `json:"components"`
+}
+
+// schemaTypes сопоставляет схемы из components.schemas типам из handlers/models
+var schemaTypes = map[string]reflect.Type{
+	"MazeRef":                  reflect.TypeFor[models.MazeRef](),
+	"Point":                    reflect.TypeFor[models.Point](),
+	"Cell":                     reflect.TypeFor[models.Cell](),
+	"Coords":                   reflect.TypeFor[models.Coords](),
+	"Tranzition":               reflect.TypeFor[models.Tranzition](),
+	"SolveMazeInput":           reflect.TypeFor[models.SolveMazeInput](),
+	"SolveMazeOutput":          reflect.TypeFor[models.SolveMazeOutput](),
+	"BatchSolveMazeInput":      reflect.TypeFor[models.BatchSolveMazeInput](),
+	"BatchQuery":               reflect.TypeFor[models.BatchQuery](),
+	"BatchSolveMazeOutput":     reflect.TypeFor[models.BatchSolveMazeOutput](),
+	"BatchResult":              reflect.TypeFor[models.BatchResult](),
+	"CompareInput":             reflect.TypeFor[models.CompareInput](),
+	"CompareOutput":            reflect.TypeFor[models.CompareOutput](),
+	"CompareResult":            reflect.TypeFor[models.CompareResult](),
+	"UpdateMazeInput":          reflect.TypeFor[models.UpdateMazeInput](),
+	"UpdateMazeOutput":         reflect.TypeFor[models.UpdateMazeOutput](),
+	"GetMazeOutput":            reflect.TypeFor[models.GetMazeOutput](),
+	"RestoreMazeOutput":        reflect.TypeFor[models.RestoreMazeOutput](),
+	"MazeSummaryV2":            reflect.TypeFor[models.MazeSummaryV2](),
+	"ListMazesOutputV2":        reflect.TypeFor[models.ListMazesOutputV2](),
+	"ExitDistanceOutputV2":     reflect.TypeFor[models.ExitDistanceOutputV2](),
+	"MazeOutputV2":             reflect.TypeFor[models.MazeOutputV2](),
+	"LandmarksV2":              reflect.TypeFor[models.LandmarksV2](),
+	"CellPatchV2":              reflect.TypeFor[models.CellPatchV2](),
+	"PatchCellsInputV2":        reflect.TypeFor[models.PatchCellsInputV2](),
+	"FindPathInputV2":          reflect.TypeFor[models.FindPathInputV2](),
+	"FindPathOutputV2":         reflect.TypeFor[models.FindPathOutputV2](),
+	"AnytimePathInputV2":       reflect.TypeFor[models.AnytimePathInputV2](),
+	"AnytimeSolutionV2":        reflect.TypeFor[models.AnytimeSolutionV2](),
+	"AnytimePathOutputV2":      reflect.TypeFor[models.AnytimePathOutputV2](),
+	"AlternativePathsInputV2":  reflect.TypeFor[models.AlternativePathsInputV2](),
+	"AlternativePathV2":        reflect.TypeFor[models.AlternativePathV2](),
+	"AlternativePathsOutputV2": reflect.TypeFor[models.AlternativePathsOutputV2](),
+	"AgentV2":                  reflect.TypeFor[models.AgentV2](),
+	"MultiAgentInputV2":        reflect.TypeFor[models.MultiAgentInputV2](),
+	"AgentPathV2":              reflect.TypeFor[models.AgentPathV2](),
+	"MultiAgentOutputV2":       reflect.TypeFor[models.MultiAgentOutputV2](),
+	"HealthOutput":             reflect.TypeFor[models.HealthOutput](),
+	"ReadyOutput":              reflect.TypeFor[models.ReadyOutput](),
+	"ReadyCheck":               reflect.TypeFor[models.ReadyCheck](),
+	"VersionOutput":            reflect.TypeFor[models.VersionOutput](),
+	"AlgorithmInfo":            reflect.TypeFor[models.AlgorithmInfo](),
+	"ErrorOutput":              reflect.TypeFor[apierror.ErrorOutput](),
+	"ErrorBody":                reflect.TypeFor[apierror.ErrorBody](),
+}
+
+// operations описывает, какие типы принимает и возвращает каждый обработчик.
+// Пустое имя означает, что у запроса нет тела или ответ не описывается типом из handlers/models
+var operations = []struct {
+	method   string
+	path     string
+	request  string
+	response string
+	query    reflect.Type
+}{
+	{method: "get", path: "/healthz", response: "HealthOutput"},
+	{method: "get", path: "/readyz", response: "ReadyOutput"},
+	{method: "get", path: "/version", response: "VersionOutput"},
+	{method: "post", path: "/api/v1/calc_path", request: "SolveMazeInput", response: "SolveMazeOutput"},
+	{method: "post", path: "/api/v1/calc_path/batch", request: "BatchSolveMazeInput", response: "BatchSolveMazeOutput"},
+	{method: "post", path: "/api/v1/compare", request: "CompareInput", response: "CompareOutput"},
+	{method: "post", path: "/api/v1/update_map", request: "UpdateMazeInput", response: "UpdateMazeOutput"},
+	{method: "get", path: "/api/v1/get_map", response: "GetMazeOutput", query: reflect.TypeFor[models.GetMazeInput]()},
+	{method: "get", path: "/api/v1/restore_map", response: "RestoreMazeOutput", query: reflect.TypeFor[models.RestoreMazeInput]()},
+	{method: "get", path: "/api/v1/openapi.json"},
+	{method: "get", path: "/api/v1/docs"},
+	{method: "get", path: "/api/v1/docs/{file}"},
+	{method: "get", path: "/api/v2/mazes", response: "ListMazesOutputV2"},
+	{method: "get", path: "/api/v2/mazes/{id}", response: "MazeOutputV2"},
+	{method: "patch", path: "/api/v2/mazes/{id}/cells", request: "PatchCellsInputV2", response: "MazeOutputV2"},
+	{method: "get", path: "/api/v2/mazes/{id}/exit_distance", response: "ExitDistanceOutputV2"},
+	{method: "post", path: "/api/v2/mazes/{id}/paths", request: "FindPathInputV2", response: "FindPathOutputV2"},
+	{method: "post", path: "/api/v2/mazes/{id}/paths:anytime", request: "AnytimePathInputV2", response: "AnytimePathOutputV2"},
+	{method: "post", path: "/api/v2/mazes/{id}/paths:alternatives", request: "AlternativePathsInputV2", response: "AlternativePathsOutputV2"},
+	{method: "post", path: "/api/v2/mazes/{id}/paths:multi-agent", request: "MultiAgentInputV2", response: "MultiAgentOutputV2"},
+	{method: "post", path: "/api/v2/mazes/{id}:restore", response: "MazeOutputV2"},
+}
+
+const schemaRefPrefix = "#/components/schemas/"
+
+func loadSpec(t *testing.T) openAPISpec {
+	t.Helper()
+
+	data, err := docs.FS.ReadFile(docs.SpecFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec openAPISpec
+	if err = json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("failed to parse %s: %v", docs.SpecFile, err)
+	}
+
+	return spec
+}
+
+// jsonField описывает поле структуры так, как его видит encoding/json
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		omitempty := slices.Contains(strings.Split(options, ","), "omitempty")
+		fields = append(fields, jsonField{
+			name:     name,
+			typ:      field.Type,
+			required: !omitempty && field.Type.Kind() != reflect.Pointer,
+		})
+	}
+	return fields
+}
+
+// checkType проверяет, что схема соответствует типу Go
+func checkType(t *testing.T, where string, schema *openAPISchema, typ reflect.Type) {
+	t.Helper()
+
+	if schema == nil {
+		t.Errorf("%s: schema is missing", where)
+		return
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	for name, named := range schemaTypes {
+		if named == typ {
+			if schema.Ref != schemaRefPrefix+name {
+				t.Errorf("%s: expected $ref %q, got %+v", where, schemaRefPrefix+name, schema)
+			}
+			return
+		}
+	}
+
+	want := openAPIType(typ)
+	if want == "" {
+		t.Errorf("%s: type %s has no schema in schemaTypes", where, typ)
+		return
+	}
+
+	if schema.Type != want {
+		t.Errorf("%s: expected type %q for %s, got %q", where, want, typ, schema.Type)
+		return
+	}
+	switch want {
+	case "array":
+		checkType(t, where+"[]", schema.Items, typ.Elem())
+	case "object":
+		checkType(t, where+"{}", schema.AdditionalProperties, typ.Elem())
+	}
+}
+
+// openAPIType возвращает тип схемы для типа Go, не являющегося структурой
+func openAPIType(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice:
+		return "array"
+	case reflect.Map:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+func TestOpenAPISchemasMatchModels(t *testing.T) {
+	spec := loadSpec(t)
+
+	for name := range spec.Components.Schemas {
+		if _, found := schemaTypes[name]; !found {
+			t.Errorf("schema %s has no type in handlers/models or apierror", name)
+		}
+	}
+
+	for name, typ := range schemaTypes {
+		schema, found := spec.Components.Schemas[name]
+		if !found {
+			t.Errorf("type %s is not described in components.schemas", name)
+			continue
+		}
+
+		// Тип с собственным разбором JSON описывается через oneOf
+		if typ.Implements(reflect.TypeFor[json.Unmarshaler]()) || reflect.PointerTo(typ).Implements(reflect.TypeFor[json.Unmarshaler]()) {
+			if len(schema.OneOf) == 0 {
+				t.Errorf("%s: type with custom JSON must be described with oneOf", name)
+			}
+			continue
+		}
+
+		if typ.Kind() != reflect.Struct {
+			if want := openAPIType(typ); schema.Type != want {
+				t.Errorf("%s: expected type %q, got %q", name, want, schema.Type)
+			}
+			continue
+		}
+
+		if schema.Type != "object" {
+			t.Errorf("%s: expected type object, got %q", name, schema.Type)
+		}
+
+		fields := jsonFields(typ)
+		var required []string
+		for _, field := range fields {
+			property, found := schema.Properties[field.name]
+			if !found {
+				t.Errorf("%s: field %q is not described", name, field.name)
+				continue
+			}
+			checkType(t, name+"."+field.name, property, field.typ)
+			if field.required {
+				required = append(required, field.name)
+			}
+		}
+
+		for property := range schema.Properties {
+			if !slices.ContainsFunc(fields, func(field jsonField) bool { return field.name == property }) {
+				t.Errorf("%s: property %q does not exist in %s", name, property, typ)
+			}
+		}
+
+		slices.Sort(required)
+		specRequired := slices.Clone(schema.Required)
+		slices.Sort(specRequired)
+		if !slices.Equal(required, specRequired) {
+			t.Errorf("%s: required = %v, fields without omitempty = %v", name, specRequired, required)
+		}
+	}
+}
+
+func TestOpenAPIEnums(t *testing.T) {
+	spec := loadSpec(t)
+
+	coords := spec.Components.Schemas["Coords"].Enum
+	if !slices.Equal(coords, []string{string(models.CoordsXY), string(models.CoordsRowCol)}) {
+		t.Errorf("Coords enum = %v", coords)
+	}
+
+	codes := spec.Components.Schemas["ErrorBody"].Properties["code"].Enum
+	if !slices.Equal(codes, apierror.Codes) {
+		t.Errorf("ErrorBody.code enum = %v, apierror.Codes = %v", codes, apierror.Codes)
+	}
+}
+
+func TestOpenAPIOperationsMatchHandlers(t *testing.T) {
+	spec := loadSpec(t)
+
+	for _, op := range operations {
+		operation := spec.Paths[op.path][op.method]
+		if operation == nil {
+			t.Errorf("%s %s is not described", strings.ToUpper(op.method), op.path)
+			continue
+		}
+		where := operation.OperationID
+
+		if op.request == "" {
+			if operation.RequestBody != nil {
+				t.Errorf("%s: unexpected request body", where)
+			}
+		} else if operation.RequestBody == nil {
+			t.Errorf("%s: request body is not described", where)
+		} else {
+			checkType(t, where+" request", operation.RequestBody.Content["application/json"].Schema, schemaTypes[op.request])
+		}
+
+		if op.response != "" {
+			checkType(t, where+" response", operation.Responses["200"].Content["application/json"].Schema, schemaTypes[op.response])
+		}
+
+		for code, response := range operation.Responses {
+			if code == "200" {
+				continue
+			}
+			// Ответ, описанный на месте, а не ссылкой на components.responses, должен иметь тот же тип, что и успешный,
+			// как 503 у /readyz
+			if response.Ref == "" {
+				checkType(t, where+" response "+code, response.Content["application/json"].Schema, schemaTypes[op.response])
+				continue
+			}
+			name := strings.TrimPrefix(response.Ref, "#/components/responses/")
+			media, found := spec.Components.Responses[name].Content["application/json"]
+			if !found || media.Schema == nil || media.Schema.Ref != schemaRefPrefix+"ErrorOutput" {
+				t.Errorf("%s: response %s must be ErrorOutput", where, code)
+			}
+		}
+
+		if op.query != nil {
+			for _, field := range jsonFields(op.query) {
+				if !slices.ContainsFunc(operation.Parameters, func(parameter openAPIParameter) bool {
+					parameter = resolveParameter(spec, parameter)
+					return parameter.In == "query" && parameter.Name == field.name && parameter.Required == field.required
+				}) {
+					t.Errorf("%s: query parameter %q is not described", where, field.name)
+				}
+			}
+		}
+
+		for _, name := range pathParameters(op.path) {
+			if !slices.ContainsFunc(operation.Parameters, func(parameter openAPIParameter) bool {
+				parameter = resolveParameter(spec, parameter)
+				return parameter.In == "path" && parameter.Name == name && parameter.Required
+			}) {
+				t.Errorf("%s: path parameter %q is not described", where, name)
+			}
+		}
+	}
+
+	described := 0
+	for _, item := range spec.Paths {
+		described += len(item)
+	}
+	if described != len(operations) {
+		t.Errorf("spec describes %d operations, test covers %d", described, len(operations))
+	}
+}
+
+func resolveParameter(spec openAPISpec, parameter openAPIParameter) openAPIParameter {
+	if parameter.Ref != "" {
+		return spec.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+	}
+	return parameter
+}
+
+var pathParameterRe = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+func pathParameters(path string) []string {
+	var names []string
+	for _, match := range pathParameterRe.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func TestOpenAPIDescribesAllRoutes(t *testing.T) {
+	spec := loadSpec(t)
+
+	router := mux.NewRouter()
+	app, err := NewApp(config.AppConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.RegisterRoutes(router)
+
+	registered := make(map[string]bool)
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		path := pathParameterRe.ReplaceAllString(template, "{$1}")
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+			method = strings.ToLower(method)
+			registered[method+" "+path] = true
+			if spec.Paths[path][method] == nil {
+				t.Errorf("route %s %s is not described in %s", strings.ToUpper(method), path, docs.SpecFile)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, item := range spec.Paths {
+		for method := range item {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is described in %s, but not registered", strings.ToUpper(method), path, docs.SpecFile)
+			}
+		}
+	}
+}
+
+func TestDocsHandlers(t *testing.T) {
+	router := mux.NewRouter()
+	app, err := NewApp(config.AppConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.RegisterRoutes(router)
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{path: "/api/v1/openapi.json", status: http.StatusOK, contentType: "application/json"},
+		{path: "/api/v1/docs", status: http.StatusOK, contentType: "text/html; charset=utf-8"},
+		{path: "/api/v1/docs/docs.js", status: http.StatusOK, contentType: "text/javascript; charset=utf-8"},
+		{path: "/api/v1/docs/docs.css", status: http.StatusOK, contentType: "text/css; charset=utf-8"},
+		{path: "/api/v1/docs/missing.js", status: http.StatusNotFound, contentType: "application/json; charset=utf-8"},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if recorder.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.path, recorder.Code, test.status)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != test.contentType {
+			t.Errorf("%s: Content-Type %q, want %q", test.path, contentType, test.contentType)
+		}
+	}
+}
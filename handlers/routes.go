@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterRoutes регистрирует служебные маршруты и маршруты API v1 и v2.
+// Каждый маршрут должен быть описан в handlers/docs/openapi.json
+func (app *App) RegisterRoutes(router *mux.Router) {
+	router.Handle("/healthz", http.HandlerFunc(app.HealthHandler)).Methods(http.MethodGet, http.MethodOptions)
+	router.Handle("/readyz", http.HandlerFunc(app.ReadyHandler)).Methods(http.MethodGet, http.MethodOptions)
+	router.Handle("/version", http.HandlerFunc(app.VersionHandler)).Methods(http.MethodGet, http.MethodOptions)
+
+	r := router.PathPrefix("/api/v1").Subrouter()
+
+	r.Handle("/calc_path", http.HandlerFunc(app.SolveMazeHandler)).Methods(http.MethodPost, http.MethodOptions)
+	r.Handle("/calc_path/batch", http.HandlerFunc(app.SolveMazeBatchHandler)).Methods(http.MethodPost, http.MethodOptions)
+	r.Handle("/compare", http.HandlerFunc(app.CompareHandler)).Methods(http.MethodPost, http.MethodOptions)
+	r.Handle("/update_map", http.HandlerFunc(app.UpdateMazeHandler)).Methods(http.MethodPost, http.MethodOptions)
+	r.Handle("/get_map", http.HandlerFunc(app.GetMazeHandler)).Methods(http.MethodGet, http.MethodOptions)
+	r.Handle("/restore_map", http.HandlerFunc(app.RestoreMazeHandler)).Methods(http.MethodGet, http.MethodOptions)
+
+	r.Handle("/openapi.json", http.HandlerFunc(app.OpenAPIHandler)).Methods(http.MethodGet, http.MethodOptions)
+	r.Handle("/docs", http.HandlerFunc(app.DocsHandler)).Methods(http.MethodGet, http.MethodOptions)
+	r.Handle("/docs/{file}", http.HandlerFunc(app.DocsAssetHandler)).Methods(http.MethodGet, http.MethodOptions)
+
+	r2 := router.PathPrefix("/api/v2").Subrouter()
+
+	r2.Handle("/mazes", http.HandlerFunc(app.ListMazesHandlerV2)).Methods(http.MethodGet, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}", http.HandlerFunc(app.GetMazeHandlerV2)).Methods(http.MethodGet, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/cells", http.HandlerFunc(app.PatchCellsHandlerV2)).Methods(http.MethodPatch, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/exit_distance", http.HandlerFunc(app.ExitDistanceHandlerV2)).Methods(http.MethodGet, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/paths", http.HandlerFunc(app.FindPathHandlerV2)).Methods(http.MethodPost, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/paths:anytime", http.HandlerFunc(app.AnytimePathHandlerV2)).Methods(http.MethodPost, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/paths:alternatives", http.HandlerFunc(app.AlternativePathsHandlerV2)).Methods(http.MethodPost, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}/paths:multi-agent", http.HandlerFunc(app.MultiAgentPathsHandlerV2)).Methods(http.MethodPost, http.MethodOptions)
+	r2.Handle("/mazes/{id:[^/:]+}:restore", http.HandlerFunc(app.RestoreMazeHandlerV2)).Methods(http.MethodPost, http.MethodOptions)
+}
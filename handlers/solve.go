@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"algo/algorithms"
+	"algo/algorithms/dijkstra"
+	"algo/algorithms/exit_distance"
+	"algo/algorithms/heuristics"
+	"algo/algorithms/waypoints"
+	"algo/apierror"
+	"algo/handlers/models"
+	"algo/metrics"
+	"github.com/pkg/errors"
+)
+
+// exitFieldSolver имя в метриках для путей, восстановленных по полю расстояний до выходов
+const exitFieldSolver = "exit-field"
+
+// solution представляет собой результат решения одной задачи
+type solution struct {
+	result  algorithms.Result
+	elapsed time.Duration
+	bound   float64 // Граница субоптимальности пути, 0 — путь кратчайший или граница неизвестна
+	solver  string  // Имя алгоритма, который построил путь, или exitFieldSolver
+
+	order      []int // Номера промежуточных клеток в порядке обхода
+	exactOrder bool  // Порядок обхода задан клиентом или найден точно
+}
+
+// buildTargets проверяет стартовую и конечные клетки и возвращает список целей для алгоритма
+func buildTargets(board [][]bool, start models.Cell, end []models.Cell) ([][2]int, error) {
+	if board[start.Row][start.Col] {
+		return nil, apierror.NewInvalidError(apierror.CodeStartIsWall, fmt.Sprintf("start cell (row=%d, col=%d) is wall", start.Row, start.Col))
+	}
+
+	if len(end) == 0 {
+		return algorithms.GetBoundaryCells(board, start.Row, start.Col), nil
+	}
+
+	targets := make([][2]int, 0, len(end))
+	for i, cell := range end {
+		if board[cell.Row][cell.Col] {
+			return nil, apierror.NewInvalidIndexError(apierror.CodeEndIsWall, i, fmt.Sprintf("end cell (row=%d, col=%d) is wall", cell.Row, cell.Col))
+		}
+		targets = append(targets, [2]int{cell.Row, cell.Col})
+	}
+
+	return targets, nil
+}
+
+// runAlgorithm запускает алгоритм и замеряет время его работы
+func runAlgorithm(algorithm algorithms.Algorithm, board [][]bool, start models.Cell, targets [][2]int) solution {
+	var result algorithms.Result
+	elapsed := metrics.Solve(algorithm.Name, func() int {
+		result = algorithm.Solve(board, start.Row, start.Col, targets)
+		return result.Expanded
+	})
+	return solution{result: result, elapsed: elapsed, solver: algorithm.Name}
+}
+
+// solveWith ищет путь выбранным алгоритмом
+func solveWith(algorithm algorithms.Algorithm, board [][]bool, start models.Cell, end []models.Cell) (solution, error) {
+	targets, err := buildTargets(board, start, end)
+	if err != nil {
+		return solution{}, err
+	}
+
+	sol := runAlgorithm(algorithm, board, start, targets)
+	if sol.result.Dist != algorithms.PathNotFound && len(sol.result.Path) == 0 {
+		return solution{}, errors.New("path is empty")
+	}
+
+	return sol, nil
+}
+
+// solve ищет путь для задачи из ключа. Путь до ближайшего выхода для точных алгоритмов, которые ищут путь по соседним клеткам,
+// восстанавливается по полю расстояний без поиска: такой путь тоже кратчайший, но время и статистика относятся к спуску
+// по полю, поэтому в ответе указывается, какой алгоритм построил путь. Алгоритмы с предобработкой ищут путь
+// по индексу текущей версии лабиринта. Путь через промежуточные клетки складывается из участков, найденных алгоритмом
+func (app *App) solve(key solveKey, board [][]bool, start models.Cell, end []models.Cell, via []models.Cell) (solution, error) {
+	algorithm, found := algorithms.Get(key.algorithmID)
+	if !found {
+		return solution{}, apierror.NewInvalidError(apierror.CodeInvalidAlgorithmID, fmt.Sprintf("algorithm %d does not exist", key.algorithmID))
+	}
+
+	// Поиск с выбранными весом, эвристикой или моделью перемещения выполняется всегда, иначе клиент не увидит,
+	// как они влияют на путь. Поле расстояний до выходов и индексы построены для ходов по сторонам
+	configured := key.weight > 1 || key.heuristic != "" || key.movement != ""
+	if configured {
+		options := algorithms.Options{Weight: key.weight}
+		if key.movement != "" {
+			movement, found := heuristics.GetMovement(key.movement)
+			if !found {
+				return solution{}, apierror.NewInvalidError(apierror.CodeInvalidMovement, fmt.Sprintf("movement %q does not exist", key.movement))
+			}
+			options.Movement = movement
+		}
+		if key.heuristic != "" {
+			h, found := heuristics.Get(key.heuristic)
+			if !found {
+				return solution{}, apierror.NewInvalidError(apierror.CodeInvalidHeuristic, fmt.Sprintf("heuristic %q does not exist", key.heuristic))
+			}
+			options.Heuristic = h
+		}
+		algorithm.Solve = algorithm.Configure(options)
+	}
+
+	if !configured && len(via) == 0 && !algorithm.AnyAngle && !algorithm.Approximate && len(end) == 0 && !board[start.Row][start.Col] {
+		field := app.exits.get(key.mazeID, key.version, board)
+		// Стартовая клетка не считается целью, поэтому для старта на выходе нужен обычный поиск
+		if !field.IsExit(start.Row, start.Col) {
+			return runExitField(field, start), nil
+		}
+	}
+
+	if !configured && algorithm.Preprocess != nil && !board[start.Row][start.Col] {
+		index := app.indexes.get(key.mazeID, key.version, algorithm, board)
+		algorithm.Solve = func(_ [][]bool, startX, startY int, targets [][2]int) algorithms.Result {
+			return index.Solve(startX, startY, targets)
+		}
+	}
+
+	var (
+		sol solution
+		err error
+	)
+	if len(via) > 0 {
+		sol, err = solveVia(algorithm, board, start, end, via, strings.HasPrefix(key.via, models.ViaOptimal))
+	} else {
+		sol, err = solveWith(algorithm, board, start, end)
+	}
+	if err != nil {
+		return solution{}, err
+	}
+
+	// Для пути через промежуточные клетки граница верна, только если порядок обхода не подобран эвристикой
+	if key.weight > 1 && (len(via) == 0 || sol.exactOrder) {
+		sol.bound = key.weight
+	}
+	return sol, nil
+}
+
+// solveVia ищет путь от старта через все промежуточные клетки до ближайшей из целей. Если optimal ложно,
+// клетки обходятся в порядке запроса
+func solveVia(algorithm algorithms.Algorithm, board [][]bool, start models.Cell, end []models.Cell, via []models.Cell, optimal bool) (solution, error) {
+	targets, err := buildTargets(board, start, end)
+	if err != nil {
+		return solution{}, err
+	}
+
+	cells := make([][2]int, len(via))
+	for i, cell := range via {
+		if board[cell.Row][cell.Col] {
+			return solution{}, apierror.NewInvalidIndexError(apierror.CodeViaIsWall, i, fmt.Sprintf("via cell (row=%d, col=%d) is wall", cell.Row, cell.Col))
+		}
+		cells[i] = [2]int{cell.Row, cell.Col}
+	}
+
+	var route waypoints.Route
+	elapsed := metrics.Solve(algorithm.Name, func() int {
+		route = waypoints.Solve(board, [2]int{start.Row, start.Col}, cells, targets, optimal, algorithm.Solve)
+		return route.Expanded
+	})
+	return solution{result: route.Result, elapsed: elapsed, solver: algorithm.Name, order: route.Order, exactOrder: route.Exact}, nil
+}
+
+// runExitField восстанавливает путь до ближайшего выхода по полю расстояний
+func runExitField(field *exit_distance.Field, start models.Cell) solution {
+	var result algorithms.Result
+	elapsed := metrics.Solve(exitFieldSolver, func() int {
+		result = field.Nearest(start.Row, start.Col)
+		return result.Expanded
+	})
+	return solution{result: result, elapsed: elapsed, solver: exitFieldSolver}
+}
+
+// compareAlgorithms запускает все зарегистрированные алгоритмы на одной задаче
+// и сравнивает найденные расстояния с эталонным результатом алгоритма Дейкстры
+func compareAlgorithms(board [][]bool, start models.Cell, end []models.Cell) (models.CompareOutput, error) {
+	var output models.CompareOutput
+
+	targets, err := buildTargets(board, start, end)
+	if err != nil {
+		return output, err
+	}
+
+	reference := dijkstra.Dijkstra(board, start.Row, start.Col, targets)
+	output.OptimalDist = reference.Dist
+
+	for _, algorithm := range algorithms.All() {
+		sol := runAlgorithm(algorithm, board, start, targets)
+		output.Results = append(output.Results, models.CompareResult{
+			AlgorithmID:   algorithm.ID,
+			Name:          algorithm.Title,
+			Found:         sol.result.Dist != algorithms.PathNotFound,
+			Dist:          sol.result.Dist,
+			PathNodes:     len(sol.result.Path),
+			Expanded:      sol.result.Expanded,
+			ExecutionTime: sol.elapsed,
+			Optimal:       sol.result.Dist == reference.Dist,
+			Stats:         sol.result.Stats,
+		})
+	}
+
+	return output, nil
+}
+
+// toSolveMazeOutput формирует ответ API v1, пустой ответ означает, что путь не найден
+func toSolveMazeOutput(sol solution, coords models.Coords) models.SolveMazeOutput {
+	if sol.result.Dist == algorithms.PathNotFound {
+		return models.SolveMazeOutput{}
+	}
+
+	path := toCells(sol.result.Path)
+	output := models.SolveMazeOutput{
+		Path:          make([]models.Tranzition, len(path)-1),
+		Dist:          sol.result.Dist,
+		ExecutionTime: sol.elapsed,
+		Stats:         sol.result.Stats,
+		Solver:        sol.solver,
+
+		SuboptimalityBound: sol.bound,
+		VisitOrder:         sol.order,
+	}
+	for i := 1; i < len(path); i++ {
+		output.Path[i-1] = models.Tranzition{
+			Start: coords.Point(path[i-1]),
+			End:   coords.Point(path[i]),
+		}
+	}
+
+	return output
+}
+
+// toFindPathOutputV2 формирует ответ API v2, пустой путь означает, что путь не найден
+func toFindPathOutputV2(sol solution) models.FindPathOutputV2 {
+	if sol.result.Dist == algorithms.PathNotFound {
+		return models.FindPathOutputV2{Path: []models.Cell{}, Dist: algorithms.PathNotFound, ExecutionTime: sol.elapsed, Solver: sol.solver}
+	}
+
+	return models.FindPathOutputV2{
+		Path:          toCells(sol.result.Path),
+		Dist:          sol.result.Dist,
+		ExecutionTime: sol.elapsed,
+		Stats:         sol.result.Stats,
+		Solver:        sol.solver,
+
+		SuboptimalityBound: sol.bound,
+		VisitOrder:         sol.order,
+	}
+}
+
+func toCells(path []algorithms.Node) []models.Cell {
+	cells := make([]models.Cell, len(path))
+	for i, node := range path {
+		cells[i] = models.Cell{Row: node.X, Col: node.Y}
+	}
+	return cells
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"algo/algorithms"
+	"algo/algorithms/heuristics"
+	"algo/handlers/models"
+	"algo/metrics"
+)
+
+// cacheHeader заголовок ответа со значением hit или miss
+const cacheHeader = "X-Cache"
+
+// solveKey ключ кэша результатов поиска пути. Версия лабиринта — хеш его клеток,
+// поэтому результат, посчитанный до изменения лабиринта, не может быть выдан после него
+type solveKey struct {
+	mazeID      int
+	version     uint64
+	algorithmID int
+	weight      float64 // Вес эвристики, 0 — поиск без веса
+	heuristic   string  // Имя эвристики, пустая строка — эвристика модели перемещения по умолчанию
+	movement    string  // Имя модели перемещения, пустая строка — grid4
+	start       models.Cell
+	targets     string // Отсортированные конечные клетки без повторов, пустая строка — все выходы на границе
+	via         string // Порядок обхода и промежуточные клетки в порядке запроса, пустая строка — без промежуточных клеток
+}
+
+func newSolveKey(mazeID int, version uint64, algorithmID int, weight float64, heuristic, movement string, start models.Cell, end []models.Cell) solveKey {
+	cells := slices.Clone(end)
+	slices.SortFunc(cells, func(a, b models.Cell) int {
+		return cmp.Or(cmp.Compare(a.Row, b.Row), cmp.Compare(a.Col, b.Col))
+	})
+	cells = slices.Compact(cells)
+
+	// Вес 1 не меняет поиск, поэтому такие запросы попадают в тот же ключ, что и запросы без веса
+	if weight == 1 {
+		weight = 0
+	}
+	// grid4 — модель перемещения по умолчанию у всех алгоритмов, которые позволяют ее выбрать, а эвристика
+	// по умолчанию — эвристика модели перемещения
+	selected := heuristics.Grid4
+	if m, found := heuristics.GetMovement(movement); found {
+		selected = m
+	}
+	if movement == heuristics.Grid4.Name {
+		movement = ""
+	}
+	if heuristic == selected.Heuristic.Name() {
+		heuristic = ""
+	}
+
+	return solveKey{
+		mazeID: mazeID, version: version, algorithmID: algorithmID, weight: weight, heuristic: heuristic, movement: movement,
+		start: start, targets: formatCells(cells),
+	}
+}
+
+// withVia возвращает ключ для пути через промежуточные клетки. Номера клеток в ответе зависят от порядка
+// в запросе, поэтому клетки не сортируются
+func (key solveKey) withVia(via []models.Cell, order string) solveKey {
+	if len(via) == 0 {
+		return key
+	}
+	if order == "" {
+		order = models.ViaFixed
+	}
+	key.via = order + ":" + formatCells(via)
+	return key
+}
+
+func formatCells(cells []models.Cell) string {
+	var builder strings.Builder
+	for i, cell := range cells {
+		if i > 0 {
+			builder.WriteByte(';')
+		}
+		builder.WriteString(strconv.Itoa(cell.Row))
+		builder.WriteByte(',')
+		builder.WriteString(strconv.Itoa(cell.Col))
+	}
+	return builder.String()
+}
+
+// solveCached ищет путь, используя кэш результатов. Ошибки не кэшируются
+func (app *App) solveCached(key solveKey, board [][]bool, start models.Cell, end []models.Cell, via []models.Cell) (solution, bool, error) {
+	// Результат алгоритма, ограниченного временем, зависит от нагрузки, поэтому каждый запрос ищет путь заново
+	if algorithm, found := algorithms.Get(key.algorithmID); found && algorithm.TimeBounded {
+		sol, err := app.solve(key, board, start, end, via)
+		return sol, false, err
+	}
+
+	if sol, found := app.cache.Get(key); found {
+		metrics.ObserveCacheLookup(true)
+		return sol, true, nil
+	}
+	metrics.ObserveCacheLookup(false)
+
+	sol, err := app.solve(key, board, start, end, via)
+	if err != nil {
+		return solution{}, false, err
+	}
+
+	app.cache.Add(key, sol)
+	metrics.SetCacheEntries(app.cache.Len())
+	return sol, false, nil
+}
+
+// invalidateMaze удаляет из кэша результаты для лабиринта. Устаревшие записи и так не будут найдены
+// из-за смены версии, удаление лишь освобождает место
+func (app *App) invalidateMaze(mazeID int) {
+	app.cache.RemoveFunc(func(key solveKey) bool {
+		return key.mazeID == mazeID
+	})
+	metrics.SetCacheEntries(app.cache.Len())
+}
+
+// setCacheHeader сообщает клиенту, был ли результат взят из кэша
+func setCacheHeader(w http.ResponseWriter, cached bool) {
+	if cached {
+		w.Header().Set(cacheHeader, metrics.CacheHit)
+	} else {
+		w.Header().Set(cacheHeader, metrics.CacheMiss)
+	}
+}
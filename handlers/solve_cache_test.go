@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "algo/algorithms/a_star"
+	"algo/config"
+	"algo/handlers/models"
+	"algo/maze"
+	"github.com/gorilla/mux"
+)
+
+// newTestApp создает приложение с одним лабиринтом во временном каталоге
+func newTestApp(t *testing.T, cacheSize int) http.Handler {
+	t.Helper()
+
+	return newTestAppWith(t, config.AppConfig{CacheSize: cacheSize})
+}
+
+// newTestAppWith создает приложение с настройками cfg и одним лабиринтом во временном каталоге
+func newTestAppWith(t *testing.T, cfg config.AppConfig) http.Handler {
+	t.Helper()
+
+	board := [][]bool{
+		{true, false, true, true, true},
+		{true, false, false, false, true},
+		{true, true, true, false, true},
+		{true, false, false, false, true},
+		{true, false, true, true, true},
+	}
+	dir := t.TempDir()
+	path, original := filepath.Join(dir, "maze.txt"), filepath.Join(dir, "maze_default.txt")
+	for _, filename := range []string{path, original} {
+		if err := maze.SaveMaze(filename, board, maze.FormatText); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg.Mazes = []config.MazeConfig{{ID: 1, Name: "small", Path: path, Original: original}}
+	app, err := NewApp(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	app.RegisterRoutes(router)
+	return router
+}
+
+func serve(t *testing.T, handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%s %s: status %d, body %s", method, target, recorder.Code, recorder.Body)
+	}
+	return recorder
+}
+
+func TestSolveCache(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	const findPath = `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}]}`
+	steps := []struct {
+		method, target, body string
+		cache                string
+	}{
+		{method: http.MethodPost, target: "/api/v2/mazes/1/paths", body: findPath, cache: "miss"},
+		{method: http.MethodPost, target: "/api/v2/mazes/small/paths", body: findPath, cache: "hit"},
+		// Тот же запрос в v1 с другой системой координат попадает в тот же ключ
+		{method: http.MethodPost, target: "/api/v1/calc_path", body: `{"labirint_id": 1, "algorithm_id": 1, "start": {"x": 0, "y": 1}, "end": [{"x": 4, "y": 1}], "coords": "row_col"}`, cache: "hit"},
+		{method: http.MethodPost, target: "/api/v2/mazes/1/paths", body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}, {"row": 4, "col": 1}]}`, cache: "hit"},
+		{method: http.MethodPatch, target: "/api/v2/mazes/1/cells", body: `{"cells": [{"row": 2, "col": 2, "wall": false}]}`},
+		{method: http.MethodPost, target: "/api/v2/mazes/1/paths", body: findPath, cache: "miss"},
+		{method: http.MethodPost, target: "/api/v2/mazes/1/paths", body: findPath, cache: "hit"},
+		{method: http.MethodPost, target: "/api/v2/mazes/1:restore"},
+		{method: http.MethodPost, target: "/api/v2/mazes/1/paths", body: findPath, cache: "miss"},
+	}
+
+	for i, step := range steps {
+		recorder := serve(t, handler, step.method, step.target, step.body)
+		if got := recorder.Header().Get(cacheHeader); got != step.cache {
+			t.Errorf("step %d: %s %s: X-Cache = %q, want %q", i, step.method, step.target, got, step.cache)
+		}
+	}
+}
+
+func TestSolveCacheDisabled(t *testing.T) {
+	handler := newTestApp(t, 0)
+
+	const findPath = `{"algorithm_id": 1, "start": {"row": 0, "col": 1}}`
+	for range 2 {
+		recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", findPath)
+		if got := recorder.Header().Get(cacheHeader); got != "miss" {
+			t.Errorf("X-Cache = %q with disabled cache, want miss", got)
+		}
+	}
+}
+
+func TestSolveCacheSkipsTimeBounded(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	// Путь ARA* зависит от бюджета времени, поэтому повторный запрос ищет путь заново
+	const findPath = `{"algorithm_id": 7, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}]}`
+	for range 2 {
+		recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", findPath)
+		if got := recorder.Header().Get(cacheHeader); got != "miss" {
+			t.Errorf("X-Cache = %q for time-bounded algorithm, want miss", got)
+		}
+	}
+}
+
+func TestNewSolveKeySortsTargets(t *testing.T) {
+	start := models.Cell{Row: 1, Col: 1}
+	a := newSolveKey(1, 7, 1, 0, "", "", start, []models.Cell{{Row: 3, Col: 0}, {Row: 0, Col: 2}, {Row: 3, Col: 0}})
+	b := newSolveKey(1, 7, 1, 0, "", "", start, []models.Cell{{Row: 0, Col: 2}, {Row: 3, Col: 0}})
+	if a != b {
+		t.Errorf("keys differ for the same set of targets: %+v and %+v", a, b)
+	}
+
+	if newSolveKey(1, 8, 1, 0, "", "", start, nil) == newSolveKey(1, 7, 1, 0, "", "", start, nil) {
+		t.Error("keys must differ for different maze versions")
+	}
+}
+
+func TestSolveCacheSeparatesHeuristicWeights(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	steps := []struct {
+		body  string
+		cache string
+		bound string
+	}{
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}]}`, cache: "miss"},
+		// Вес 1 не меняет поиск и попадает в тот же ключ, что и запрос без веса
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "heuristic_weight": 1}`, cache: "hit"},
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "heuristic_weight": 2}`, cache: "miss", bound: `"suboptimality_bound":2`},
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "heuristic_weight": 2}`, cache: "hit", bound: `"suboptimality_bound":2`},
+		// Манхэттенская эвристика используется по умолчанию, другая эвристика — отдельный ключ
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "heuristic": "manhattan"}`, cache: "hit"},
+		{body: `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "heuristic": "zero"}`, cache: "miss"},
+	}
+
+	for i, step := range steps {
+		recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", step.body)
+		if got := recorder.Header().Get(cacheHeader); got != step.cache {
+			t.Errorf("step %d: X-Cache = %q, want %q", i, got, step.cache)
+		}
+		if body := recorder.Body.String(); strings.Contains(body, "suboptimality_bound") != (step.bound != "") || !strings.Contains(body, step.bound) {
+			t.Errorf("step %d: response %s, want %q", i, body, step.bound)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"algo/algorithms/ara_star"
+	"algo/apierror"
+	"algo/handlers/models"
+)
+
+func TestFindPathVia(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	// Лабиринт — один коридор от (0, 1) до (4, 1) через (1, 3), поэтому в заданном порядке путь дважды проходит коридор
+	steps := []struct {
+		order string
+		dist  int
+		visit []int
+	}{
+		{order: "", dist: 16, visit: []int{0, 1}},
+		{order: models.ViaFixed, dist: 16, visit: []int{0, 1}},
+		{order: models.ViaOptimal, dist: 8, visit: []int{1, 0}},
+	}
+	for _, step := range steps {
+		body := `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}], "via": [{"row": 3, "col": 1}, {"row": 1, "col": 3}], "via_order": "` + step.order + `"}`
+		recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", body)
+
+		var output models.FindPathOutputV2
+		if err := json.NewDecoder(recorder.Body).Decode(&output); err != nil {
+			t.Fatal(err)
+		}
+		if output.Dist != step.dist || len(output.Path) != step.dist+1 || !slices.Equal(output.VisitOrder, step.visit) {
+			t.Errorf("via_order %q: dist %d, %d cells, visit order %v, want dist %d and order %v",
+				step.order, output.Dist, len(output.Path), output.VisitOrder, step.dist, step.visit)
+		}
+	}
+
+	// Тот же запрос без промежуточных клеток не должен попасть в ключ кэша пути через них
+	recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "end": [{"row": 4, "col": 1}]}`)
+	if got := recorder.Header().Get(cacheHeader); got != "miss" {
+		t.Errorf("path without via: %s = %q, want miss", cacheHeader, got)
+	}
+}
+
+func TestFindPathViaValidation(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, step := range []struct {
+		via, order, code string
+	}{
+		{via: `[{"row": 5, "col": 1}]`, code: apierror.CodeViaOutOfBounds},
+		{via: `[{"row": 0, "col": 0}]`, code: apierror.CodeViaIsWall},
+		{via: `[{"row": 1, "col": 1}]`, order: "random", code: apierror.CodeInvalidRequest},
+	} {
+		body := `{"algorithm_id": 1, "start": {"row": 0, "col": 1}, "via": ` + step.via + `, "via_order": "` + step.order + `"}`
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), step.code) {
+			t.Errorf("%s: status %d, body %s", body, recorder.Code, recorder.Body)
+		}
+	}
+}
+
+func TestFindPathReportsSolver(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, step := range []struct {
+		body, solver string
+	}{
+		// Без end точный алгоритм заменяется спуском по полю расстояний до выходов
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}}`, solver: exitFieldSolver},
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "end": [{"row": 0, "col": 1}]}`, solver: "a-star"},
+		{body: `{"algorithm_id": 7, "start": {"row": 1, "col": 1}}`, solver: ara_star.Name},
+		// Поле расстояний построено для ходов по сторонам, поэтому с другой моделью перемещения выполняется поиск
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "movement": "grid8"}`, solver: "a-star"},
+	} {
+		var output models.FindPathOutputV2
+		if err := json.NewDecoder(serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", step.body).Body).Decode(&output); err != nil {
+			t.Fatal(err)
+		}
+		if output.Solver != step.solver || output.Dist != 1 {
+			t.Errorf("%s: solver %q, dist %d, want %q and 1", step.body, output.Solver, output.Dist, step.solver)
+		}
+	}
+}
+
+func TestFindPathMovementValidation(t *testing.T) {
+	handler := newTestApp(t, 10)
+
+	for _, step := range []struct {
+		body, code string
+	}{
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "movement": "hex"}`, code: apierror.CodeInvalidMovement},
+		{body: `{"algorithm_id": 3, "start": {"row": 1, "col": 1}, "movement": "grid8"}`, code: apierror.CodeInvalidMovement},
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "movement": "grid8", "via": [{"row": 3, "col": 1}]}`, code: apierror.CodeInvalidMovement},
+		// Манхэттенское расстояние переоценивает путь по диагонали
+		{body: `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "movement": "grid8", "heuristic": "manhattan"}`, code: apierror.CodeInvalidHeuristic},
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v2/mazes/1/paths", strings.NewReader(step.body)))
+		if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), step.code) {
+			t.Errorf("%s: status %d, body %s", step.body, recorder.Code, recorder.Body)
+		}
+	}
+
+	recorder := serve(t, handler, http.MethodPost, "/api/v2/mazes/1/paths", `{"algorithm_id": 1, "start": {"row": 1, "col": 1}, "movement": "grid8", "heuristic": "chebyshev"}`)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("grid8 with chebyshev: status %d, body %s", recorder.Code, recorder.Body)
+	}
+}
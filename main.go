@@ -11,11 +11,21 @@ import (
 	"os/signal"
 	"syscall"
 
-	"algo/algorithms/a_star"
-	"algo/algorithms/lazy_theta_star"
+	_ "algo/algorithms/a_star"
+	_ "algo/algorithms/alt"
+	_ "algo/algorithms/ara_star"
+	_ "algo/algorithms/bidirectional_a_star"
+	_ "algo/algorithms/bidirectional_bfs"
+	_ "algo/algorithms/dijkstra"
+	_ "algo/algorithms/greedy_best_first"
+	_ "algo/algorithms/hpa_star"
+	_ "algo/algorithms/lazy_theta_star"
+	"algo/apierror"
 	"algo/config"
 	"algo/handlers"
+	"algo/metrics"
 	"algo/middleware"
+	"algo/utils"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
@@ -30,32 +40,40 @@ func init() {
 func main() {
 	logger := slog.New(slog.NewJSONHandler(io.MultiWriter(os.Stdout), &slog.HandlerOptions{Level: slog.LevelInfo}))
 
-	cfg := config.MustLoadConfig(os.Getenv("CONFIG_FILE"), logger)
+	configFile := os.Getenv("CONFIG_FILE")
+	cfg := config.MustLoadConfig(configFile, logger)
 	logger.Info("Config file loaded")
 
-	app := handlers.NewApp(cfg.App)
+	app, err := handlers.NewApp(cfg.App)
+	if err != nil {
+		logger.Error(errors.Wrap(err, "failed to load maze catalog").Error())
+		os.Exit(1)
+	}
 
 	reqIDMiddleware := middleware.CreateRequestIDMiddleware(logger)
 
-	r := mux.NewRouter().PathPrefix("/api/v1").Subrouter()
-	r.Use(reqIDMiddleware, middleware.CorsMiddleware, middleware.RecoverMiddleware)
-
-	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-	})
-
-	r.Handle("/calc_path", http.HandlerFunc(app.SolveMazeHandler)).Methods(http.MethodPost, http.MethodOptions)
-	r.Handle("/update_map", http.HandlerFunc(app.UpdateMazeHandler)).Methods(http.MethodPost, http.MethodOptions)
-	r.Handle("/get_map", http.HandlerFunc(app.GetMazeHandler)).Methods(http.MethodGet, http.MethodOptions)
-	r.Handle("/restore_map", http.HandlerFunc(app.RestoreMazeHandler)).Methods(http.MethodGet, http.MethodOptions)
-
-	a_star.TestAStar()
-	log.Println("=================================================================================================")
-	lazy_theta_star.TestLazyThetaStar()
-
-	http.Handle("/", r)
+	router := mux.NewRouter()
+	router.Use(reqIDMiddleware, middleware.CorsMiddleware, middleware.RecoverMiddleware)
+
+	router.NotFoundHandler = reqIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteErrorBody(r.Context(), w, http.StatusNotFound, apierror.ErrorBody{
+			Code:    apierror.CodeNotFound,
+			Message: "route " + r.URL.Path + " not found",
+		})
+	}))
+	router.MethodNotAllowedHandler = reqIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteErrorBody(r.Context(), w, http.StatusMethodNotAllowed, apierror.ErrorBody{
+			Code:    apierror.CodeMethodNotAllowed,
+			Message: "method " + r.Method + " is not allowed for " + r.URL.Path,
+		})
+	}))
+
+	app.RegisterRoutes(router)
+	router.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	http.Handle("/", router)
 	server := http.Server{
-		Handler:           middleware.PathMiddleware(r),
+		Handler:           middleware.PathMiddleware(router),
 		Addr:              fmt.Sprintf(":%s", cfg.Main.Port),
 		ReadTimeout:       cfg.Main.ReadTimeout,
 		WriteTimeout:      cfg.Main.WriteTimeout,
@@ -67,12 +85,19 @@ func main() {
 	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
-			logger.Info("Server stopped")
+		err := server.ListenAndServe()
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(errors.Wrap(err, "failed to start server").Error())
+			os.Exit(1)
 		}
+		logger.Info("Server stopped")
 	}()
 	logger.Info("Server started")
 
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	go reloadConfig(reloadCh, configFile, cfg.Main, app, logger)
+
 	sig := <-signalCh
 	logger.Info("Received signal: " + sig.String())
 
@@ -83,3 +108,29 @@ func main() {
 		logger.Error(errors.Wrap(err, "failed to gracefully shutdown").Error())
 	}
 }
+
+// reloadConfig перечитывает конфигурацию по SIGHUP и применяет новые параметры приложения.
+// Параметры сервера применяются только при перезапуске, некорректная конфигурация не применяется
+func reloadConfig(reloadCh <-chan os.Signal, configFile string, mainCfg config.MainConfig, app *handlers.App, logger *slog.Logger) {
+	for range reloadCh {
+		cfg, err := config.Load(configFile)
+		if err != nil {
+			logger.Error(errors.Wrap(err, "failed to reload config, keeping previous one").Error())
+			continue
+		}
+
+		for _, warning := range cfg.Warnings() {
+			logger.Warn(warning)
+		}
+
+		if cfg.Main != mainCfg {
+			logger.Warn("Config section main changed, restart the server to apply it")
+		}
+
+		if err = app.SetConfig(cfg.App); err != nil {
+			logger.Error(errors.Wrap(err, "failed to reload maze catalog, keeping previous one").Error())
+			continue
+		}
+		logger.Info("Config reloaded")
+	}
+}
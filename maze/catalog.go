@@ -0,0 +1,92 @@
+package maze
+
+import (
+	"slices"
+	"strconv"
+
+	"algo/config"
+	"github.com/pkg/errors"
+)
+
+// Entry лабиринт из каталога
+type Entry struct {
+	config.MazeConfig
+	Format Format
+}
+
+// Restorable сообщает, можно ли восстановить исходную версию лабиринта
+func (entry Entry) Restorable() bool {
+	return entry.Original != ""
+}
+
+// Load читает текущую версию лабиринта
+func (entry Entry) Load() ([][]bool, error) {
+	return LoadMaze(entry.Path, entry.Format)
+}
+
+// Catalog хранит лабиринты из конфигурации и ищет их по идентификатору или имени
+type Catalog struct {
+	entries []Entry
+	byID    map[int]Entry
+	byName  map[string]Entry
+}
+
+// NewCatalog строит каталог по описаниям лабиринтов из конфигурации.
+// Лабиринты упорядочиваются по идентификатору, сами файлы не читаются
+func NewCatalog(mazes []config.MazeConfig) (*Catalog, error) {
+	catalog := &Catalog{
+		entries: make([]Entry, 0, len(mazes)),
+		byID:    make(map[int]Entry, len(mazes)),
+		byName:  make(map[string]Entry, len(mazes)),
+	}
+
+	for _, maze := range mazes {
+		if err := maze.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid maze %d", maze.ID)
+		}
+
+		format, err := ParseFormat(maze.Format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid maze %d", maze.ID)
+		}
+
+		if _, found := catalog.byID[maze.ID]; found {
+			return nil, errors.Errorf("duplicate maze id %d", maze.ID)
+		}
+		if _, found := catalog.byName[maze.Name]; found {
+			return nil, errors.Errorf("duplicate maze name %q", maze.Name)
+		}
+
+		entry := Entry{MazeConfig: maze, Format: format}
+		catalog.entries = append(catalog.entries, entry)
+		catalog.byID[maze.ID] = entry
+		catalog.byName[maze.Name] = entry
+	}
+
+	slices.SortFunc(catalog.entries, func(a, b Entry) int {
+		return a.ID - b.ID
+	})
+
+	return catalog, nil
+}
+
+// Resolve ищет лабиринт по ссылке: число считается идентификатором, остальное — именем
+func (catalog *Catalog) Resolve(ref string) (Entry, bool) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return catalog.Get(id)
+	}
+
+	entry, found := catalog.byName[ref]
+	return entry, found
+}
+
+// Get ищет лабиринт по идентификатору
+func (catalog *Catalog) Get(id int) (Entry, bool) {
+	entry, found := catalog.byID[id]
+	return entry, found
+}
+
+// All возвращает все лабиринты, упорядоченные по идентификатору
+func (catalog *Catalog) All() []Entry {
+	return slices.Clone(catalog.entries)
+}
@@ -0,0 +1,85 @@
+package maze
+
+import (
+	"strings"
+	"testing"
+
+	"algo/config"
+)
+
+func TestCatalogResolve(t *testing.T) {
+	catalog, err := NewCatalog([]config.MazeConfig{
+		{ID: 2, Name: "many-targets", Path: "b.txt"},
+		{ID: 1, Name: "labyrinth", Path: "a.txt", Original: "a_default.txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref   string
+		id    int
+		found bool
+	}{
+		{ref: "1", id: 1, found: true},
+		{ref: "labyrinth", id: 1, found: true},
+		{ref: "many-targets", id: 2, found: true},
+		{ref: "3", found: false},
+		{ref: "unknown", found: false},
+		{ref: "", found: false},
+	}
+
+	for _, test := range tests {
+		entry, found := catalog.Resolve(test.ref)
+		if found != test.found || entry.ID != test.id {
+			t.Errorf("Resolve(%q) = %d, %t, want %d, %t", test.ref, entry.ID, found, test.id, test.found)
+		}
+	}
+
+	all := catalog.All()
+	if len(all) != 2 || all[0].ID != 1 || all[1].ID != 2 {
+		t.Errorf("All() returned %+v, expected mazes sorted by id", all)
+	}
+	if !all[0].Restorable() || all[1].Restorable() {
+		t.Error("only maze with original must be restorable")
+	}
+	if all[0].Format != FormatText {
+		t.Errorf("default format = %q, want %q", all[0].Format, FormatText)
+	}
+}
+
+func TestCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mazes  []config.MazeConfig
+		errMsg string
+	}{
+		{
+			name:   "duplicate id",
+			mazes:  []config.MazeConfig{{ID: 1, Name: "a", Path: "a.txt"}, {ID: 1, Name: "b", Path: "b.txt"}},
+			errMsg: "duplicate maze id 1",
+		},
+		{
+			name:   "duplicate name",
+			mazes:  []config.MazeConfig{{ID: 1, Name: "a", Path: "a.txt"}, {ID: 2, Name: "a", Path: "b.txt"}},
+			errMsg: `duplicate maze name "a"`,
+		},
+		{
+			name:   "unknown format",
+			mazes:  []config.MazeConfig{{ID: 1, Name: "a", Path: "a.txt", Format: "xml"}},
+			errMsg: `unknown maze format "xml"`,
+		},
+		{
+			name:   "numeric name",
+			mazes:  []config.MazeConfig{{ID: 1, Name: "2", Path: "a.txt"}},
+			errMsg: "must not be a number",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := NewCatalog(test.mazes)
+		if err == nil || !strings.Contains(err.Error(), test.errMsg) {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.errMsg)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package maze
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format задает формат хранения лабиринта в файле
+type Format string
+
+const (
+	// FormatText клетки записаны как 0 и 1 через пробел, по строке файла на строку матрицы
+	FormatText Format = "txt"
+	// FormatCompact клетки записаны как 0 и 1 без разделителей
+	FormatCompact Format = "compact"
+	// FormatASCII стены записаны как '#', свободные клетки как '.'
+	FormatASCII Format = "ascii"
+	// FormatJSON матрица записана как JSON-массив массивов из 0 и 1
+	FormatJSON Format = "json"
+)
+
+// Formats перечисляет все поддерживаемые форматы
+var Formats = []Format{FormatText, FormatCompact, FormatASCII, FormatJSON}
+
+// ParseFormat возвращает формат по его имени, пустое имя соответствует FormatText
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return FormatText, nil
+	}
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown maze format %q", name)
+}
+
+// ReadMaze читает лабиринт в заданном формате.
+// Лабиринт должен быть непустой прямоугольной матрицей, состоящей только из обозначений стен и свободных клеток
+func ReadMaze(r io.Reader, format Format) ([][]bool, error) {
+	var (
+		board [][]bool
+		err   error
+	)
+
+	switch format {
+	case FormatText:
+		board, err = readLines(r, strings.Fields, "1", "0")
+	case FormatCompact:
+		board, err = readLines(r, splitChars, "1", "0")
+	case FormatASCII:
+		board, err = readLines(r, splitChars, "#", ".")
+	case FormatJSON:
+		board, err = readJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown maze format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateBoard(board); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// WriteMaze записывает лабиринт в заданном формате
+func WriteMaze(w io.Writer, board [][]bool, format Format) error {
+	if format == FormatJSON {
+		cells := make([][]int, len(board))
+		for i, row := range board {
+			cells[i] = make([]int, len(row))
+			for j, cell := range row {
+				if cell {
+					cells[i][j] = 1
+				}
+			}
+		}
+		return errors.Wrap(json.NewEncoder(w).Encode(cells), "failed to encode json maze")
+	}
+
+	wall, free, sep := "1", "0", ""
+	switch format {
+	case FormatText:
+		sep = " "
+	case FormatCompact:
+	case FormatASCII:
+		wall, free = "#", "."
+	default:
+		return fmt.Errorf("unknown maze format %q", format)
+	}
+
+	buf := bufio.NewWriter(w)
+	for _, row := range board {
+		for i, cell := range row {
+			if i > 0 {
+				buf.WriteString(sep)
+			}
+			if cell {
+				buf.WriteString(wall)
+			} else {
+				buf.WriteString(free)
+			}
+		}
+		buf.WriteString("\n")
+	}
+
+	return errors.Wrap(buf.Flush(), "failed to write maze")
+}
+
+// LoadMaze читает лабиринт из файла в заданном формате
+func LoadMaze(filename string, format Format) ([][]bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open maze file")
+	}
+	defer file.Close()
+
+	return ReadMaze(file, format)
+}
+
+// SaveMaze записывает лабиринт в файл в заданном формате, перезаписывая его
+func SaveMaze(filename string, board [][]bool, format Format) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+
+	if err = WriteMaze(file, board, format); err != nil {
+		file.Close()
+		return err
+	}
+
+	return errors.Wrap(file.Close(), "failed to close maze file")
+}
+
+func readLines(r io.Reader, split func(string) []string, wall, free string) ([][]bool, error) {
+	var (
+		board      [][]bool
+		blankLines int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		tokens := split(scanner.Text())
+		if len(tokens) == 0 {
+			if len(board) > 0 {
+				blankLines++
+			}
+			continue
+		}
+		if blankLines > 0 {
+			return nil, fmt.Errorf("blank line before row at line %d", line)
+		}
+
+		row := make([]bool, len(tokens))
+		for i, token := range tokens {
+			switch token {
+			case wall:
+				row[i] = true
+			case free:
+			default:
+				return nil, fmt.Errorf("unknown token %q at line %d, column %d, expected %q or %q", token, line, i+1, wall, free)
+			}
+		}
+		board = append(board, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to scan maze file")
+	}
+
+	return board, nil
+}
+
+func readJSON(r io.Reader) ([][]bool, error) {
+	var cells [][]int
+	if err := json.NewDecoder(r).Decode(&cells); err != nil {
+		return nil, errors.Wrap(err, "failed to decode json maze")
+	}
+
+	board := make([][]bool, len(cells))
+	for i, row := range cells {
+		board[i] = make([]bool, len(row))
+		for j, cell := range row {
+			switch cell {
+			case 1:
+				board[i][j] = true
+			case 0:
+			default:
+				return nil, fmt.Errorf("unknown value %d at row %d, column %d, expected 0 or 1", cell, i+1, j+1)
+			}
+		}
+	}
+
+	return board, nil
+}
+
+// validateBoard проверяет, что лабиринт является непустой прямоугольной матрицей
+func validateBoard(board [][]bool) error {
+	if len(board) == 0 || len(board[0]) == 0 {
+		return errors.New("maze is empty")
+	}
+
+	for i, row := range board {
+		if len(row) != len(board[0]) {
+			return fmt.Errorf("ragged maze: row %d has %d cells, expected %d", i+1, len(row), len(board[0]))
+		}
+	}
+
+	return nil
+}
+
+func splitChars(line string) []string {
+	return strings.Split(strings.TrimSpace(line), "")
+}
@@ -0,0 +1,101 @@
+package maze
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadMazeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		errMsg string
+	}{
+		{name: "empty text", format: FormatText, input: "", errMsg: "maze is empty"},
+		{name: "blank lines only", format: FormatText, input: "\n  \n", errMsg: "maze is empty"},
+		{name: "ragged text", format: FormatText, input: "0 1 0\n0 1\n", errMsg: "ragged maze: row 2 has 2 cells, expected 3"},
+		{name: "unknown token", format: FormatText, input: "0 1\n0 2\n", errMsg: `unknown token "2" at line 2, column 2`},
+		{name: "glued tokens", format: FormatText, input: "01\n", errMsg: `unknown token "01" at line 1, column 1`},
+		{name: "blank line inside", format: FormatText, input: "0 1\n\n1 0\n", errMsg: "blank line before row at line 3"},
+		{name: "unknown char", format: FormatCompact, input: "010\n0x0\n", errMsg: `unknown token "x" at line 2, column 2`},
+		{name: "ascii digits", format: FormatASCII, input: "#.#\n#0#\n", errMsg: `unknown token "0" at line 2, column 2`},
+		{name: "empty json", format: FormatJSON, input: "[]", errMsg: "maze is empty"},
+		{name: "empty json row", format: FormatJSON, input: "[[]]", errMsg: "maze is empty"},
+		{name: "ragged json", format: FormatJSON, input: "[[0,1],[1]]", errMsg: "ragged maze: row 2 has 1 cells, expected 2"},
+		{name: "json value", format: FormatJSON, input: "[[0,3]]", errMsg: "unknown value 3 at row 1, column 2"},
+		{name: "unknown format", format: "xml", input: "0", errMsg: `unknown maze format "xml"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadMaze(strings.NewReader(test.input), test.format)
+			if err == nil {
+				t.Fatalf("expected error containing %q", test.errMsg)
+			}
+			if !strings.Contains(err.Error(), test.errMsg) {
+				t.Fatalf("error %q does not contain %q", err, test.errMsg)
+			}
+		})
+	}
+}
+
+func TestReadMazeAllowsTrailingBlankLines(t *testing.T) {
+	board, err := ReadMaze(strings.NewReader("\n0 1\n1 0\n\n\n"), FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board) != 2 || !board[0][1] || board[1][1] {
+		t.Fatalf("unexpected board %v", board)
+	}
+}
+
+func FuzzReadMaze(f *testing.F) {
+	for _, file := range []string{"labyrinth_matrix_41x41.txt", "labyrinth_matrix_41x41_many_targets.txt"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data, uint8(0))
+	}
+	f.Add([]byte("0 1\n1 0\n"), uint8(0))
+	f.Add([]byte("0 1\n1\n"), uint8(0))
+	f.Add([]byte("01\n10\n"), uint8(1))
+	f.Add([]byte("#.\n.#\n"), uint8(2))
+	f.Add([]byte("[[0,1],[1,0]]"), uint8(3))
+
+	f.Fuzz(func(t *testing.T, data []byte, formatIndex uint8) {
+		format := Formats[int(formatIndex)%len(Formats)]
+
+		board, err := ReadMaze(bytes.NewReader(data), format)
+		if err != nil {
+			return
+		}
+
+		if len(board) == 0 || len(board[0]) == 0 {
+			t.Fatalf("%s: accepted empty maze", format)
+		}
+		for i, row := range board {
+			if len(row) != len(board[0]) {
+				t.Fatalf("%s: accepted ragged row %d", format, i)
+			}
+		}
+
+		for _, other := range Formats {
+			var buf bytes.Buffer
+			if err = WriteMaze(&buf, board, other); err != nil {
+				t.Fatalf("%s: failed to write: %v", other, err)
+			}
+			again, err := ReadMaze(&buf, other)
+			if err != nil {
+				t.Fatalf("%s: failed to read written maze: %v", other, err)
+			}
+			if !slices.EqualFunc(board, again, slices.Equal) {
+				t.Fatalf("%s: round trip changed maze", other)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Generate строит идеальный лабиринт (между любыми двумя клетками ровно один путь)
+// методом поиска в глубину с возвратом. Вход находится в клетке (1, 0), выход — в клетке (rows-2, cols-1).
+// Размеры должны быть нечетными и не меньше 3
+func Generate(rows, cols int, seed int64) ([][]bool, error) {
+	if rows < 3 || cols < 3 || rows%2 == 0 || cols%2 == 0 {
+		return nil, fmt.Errorf("maze size must be odd and at least 3x3, got %dx%d", rows, cols)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	board := make([][]bool, rows)
+	for i := range board {
+		board[i] = make([]bool, cols)
+		for j := range board[i] {
+			board[i][j] = true
+		}
+	}
+
+	directions := [][2]int{{0, 2}, {0, -2}, {2, 0}, {-2, 0}}
+	stack := [][2]int{{1, 1}}
+	board[1][1] = false
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+
+		var candidates [][2]int
+		for _, dir := range directions {
+			x, y := current[0]+dir[0], current[1]+dir[1]
+			if x > 0 && x < rows-1 && y > 0 && y < cols-1 && board[x][y] {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := candidates[rnd.Intn(len(candidates))]
+		board[(current[0]+next[0])/2][(current[1]+next[1])/2] = false
+		board[next[0]][next[1]] = false
+		stack = append(stack, next)
+	}
+
+	board[1][0] = false
+	board[rows-2][cols-1] = false
+
+	return board, nil
+}
+
+// GenerateRandom строит доску, в которой каждая клетка независимо от других является стеной с вероятностью density.
+// Клетка (0, 0) всегда остается свободной
+func GenerateRandom(rows, cols int, density float64, seed int64) ([][]bool, error) {
+	if rows < 1 || cols < 1 {
+		return nil, fmt.Errorf("maze size must be positive, got %dx%d", rows, cols)
+	}
+	if density < 0 || density > 1 {
+		return nil, fmt.Errorf("wall density must be in [0, 1], got %v", density)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	board := make([][]bool, rows)
+	for i := range board {
+		board[i] = make([]bool, cols)
+		for j := range board[i] {
+			board[i][j] = rnd.Float64() < density
+		}
+	}
+	board[0][0] = false
+
+	return board, nil
+}
@@ -1,53 +1,31 @@
 package maze
 
 import (
-	"fmt"
-	"os"
-
 	"algo/handlers/models"
 	"github.com/pkg/errors"
 )
 
-func UpdateMaze(filename string, mazeMap [][]bool, points []models.Point) ([][]bool, error) {
-	for _, point := range points {
-		mazeMap[point.X][point.Y] = !mazeMap[point.X][point.Y]
-	}
-
-	if err := os.Remove(filename); err != nil {
-		return mazeMap, errors.Wrap(err, "failed to remove maze")
-	}
-
-	newFile, err := os.Create(filename)
-	if err != nil {
-		return mazeMap, errors.Wrap(err, "failed to create file")
+// UpdateMaze меняет значения клеток на противоположные и сохраняет лабиринт в файл
+func UpdateMaze(filename string, format Format, mazeMap [][]bool, cells []models.Cell) ([][]bool, error) {
+	for _, cell := range cells {
+		mazeMap[cell.Row][cell.Col] = !mazeMap[cell.Row][cell.Col]
 	}
-	defer newFile.Close()
 
-	for _, row := range mazeMap {
-		for i, cell := range row {
-			if cell {
-				_, err = fmt.Fprint(newFile, "1")
-			} else {
-				_, err = fmt.Fprint(newFile, "0")
-			}
-
-			if i < len(row)-1 {
-				_, err = fmt.Fprint(newFile, " ")
-			}
-		}
-		_, err = fmt.Fprintln(newFile)
+	if err := SaveMaze(filename, mazeMap, format); err != nil {
+		return mazeMap, errors.Wrap(err, "failed to write maze to file")
 	}
 
-	return mazeMap, errors.Wrap(err, "failed to write maze to file")
+	return mazeMap, nil
 }
 
-func RestoreMaze(filename string, originalFilename string) ([][]bool, error) {
-	mazeMap, err := ParseMaze(originalFilename)
+// RestoreMaze перезаписывает лабиринт его исходной версией
+func RestoreMaze(filename string, originalFilename string, format Format) ([][]bool, error) {
+	mazeMap, err := LoadMaze(originalFilename, format)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse original maze")
 	}
 
-	_, err = UpdateMaze(filename, mazeMap, []models.Point{})
+	_, err = UpdateMaze(filename, format, mazeMap, []models.Cell{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to update maze")
 	}
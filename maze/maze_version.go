@@ -0,0 +1,28 @@
+package maze
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Version возвращает хеш содержимого лабиринта. Любое изменение клеток или размеров меняет версию,
+// поэтому по ней можно проверять актуальность результатов, посчитанных для прежнего лабиринта
+func Version(board [][]bool) uint64 {
+	hash := fnv.New64a()
+
+	var size [8]byte
+	for _, row := range board {
+		binary.LittleEndian.PutUint64(size[:], uint64(len(row)))
+		hash.Write(size[:])
+
+		for _, wall := range row {
+			if wall {
+				hash.Write([]byte{1})
+			} else {
+				hash.Write([]byte{0})
+			}
+		}
+	}
+
+	return hash.Sum64()
+}
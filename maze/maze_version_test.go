@@ -0,0 +1,21 @@
+package maze
+
+import "testing"
+
+func TestVersion(t *testing.T) {
+	board := [][]bool{{true, false}, {false, true}}
+	version := Version(board)
+
+	board[0][1] = true
+	if Version(board) == version {
+		t.Error("version must change after cell update")
+	}
+	board[0][1] = false
+	if Version(board) != version {
+		t.Error("version must depend only on cells")
+	}
+
+	if Version([][]bool{{true, false, false, true}}) == Version([][]bool{{true, false}, {false, true}}) {
+		t.Error("version must depend on maze shape")
+	}
+}
@@ -0,0 +1,138 @@
+// Package metrics содержит метрики Prometheus сервиса
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "algo"
+
+// UnmatchedRoute используется как значение метки route для запросов, не совпавших ни с одним маршрутом,
+// чтобы произвольные пути не порождали новые временные ряды
+const UnmatchedRoute = "unmatched"
+
+// Результаты обращения к кэшу для метрики SolveCacheLookups
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Операции над лабиринтом для метрики MazeUpdates
+const (
+	OperationUpdate  = "update"
+	OperationRestore = "restore"
+)
+
+var (
+	// HTTPRequests количество обработанных запросов по маршруту, методу и коду ответа
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration время обработки запросов по маршруту, методу и коду ответа
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// SolverDuration время работы алгоритма поиска пути
+	SolverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "solver_duration_seconds",
+		Help:      "Time spent by a pathfinding algorithm on one query.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	}, []string{"algorithm"})
+
+	// SolverExpanded количество узлов, раскрытых алгоритмом поиска пути
+	SolverExpanded = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "solver_expanded_nodes",
+		Help:      "Number of nodes expanded by a pathfinding algorithm on one query.",
+		Buckets:   prometheus.ExponentialBuckets(16, 4, 10),
+	}, []string{"algorithm"})
+
+	// SolvesInFlight количество выполняющихся в данный момент поисков пути
+	SolvesInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "solves_in_flight",
+		Help:      "Number of pathfinding queries being solved right now.",
+	}, []string{"algorithm"})
+
+	// MazeUpdates количество изменений и восстановлений лабиринтов
+	MazeUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maze_updates_total",
+		Help:      "Number of maze updates and restores.",
+	}, []string{"maze_id", "operation"})
+
+	// SolveCacheLookups количество обращений к кэшу результатов поиска пути
+	SolveCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solve_cache_lookups_total",
+		Help:      "Number of solve cache lookups by result.",
+	}, []string{"result"})
+
+	// SolveCacheEntries количество результатов в кэше
+	SolveCacheEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "solve_cache_entries",
+		Help:      "Number of results stored in the solve cache.",
+	})
+)
+
+// Handler отдает метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRequest учитывает обработанный HTTP-запрос
+func ObserveRequest(route, method string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	HTTPRequests.WithLabelValues(route, method, code).Inc()
+	HTTPRequestDuration.WithLabelValues(route, method, code).Observe(elapsed.Seconds())
+}
+
+// Solve запускает поиск пути и учитывает его в метриках. Счётчик выполняющихся поисков уменьшается отложенно,
+// поэтому не растёт, даже если поиск завершился паникой. search возвращает число раскрытых вершин
+func Solve(algorithm string, search func() int) time.Duration {
+	inFlight := SolvesInFlight.WithLabelValues(algorithm)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	startTime := time.Now()
+	expanded := search()
+	elapsed := time.Since(startTime)
+
+	SolverDuration.WithLabelValues(algorithm).Observe(elapsed.Seconds())
+	SolverExpanded.WithLabelValues(algorithm).Observe(float64(expanded))
+	return elapsed
+}
+
+// ObserveMazeUpdate учитывает изменение или восстановление лабиринта
+func ObserveMazeUpdate(mazeID int, operation string) {
+	MazeUpdates.WithLabelValues(strconv.Itoa(mazeID), operation).Inc()
+}
+
+// ObserveCacheLookup учитывает обращение к кэшу результатов
+func ObserveCacheLookup(hit bool) {
+	result := CacheMiss
+	if hit {
+		result = CacheHit
+	}
+	SolveCacheLookups.WithLabelValues(result).Inc()
+}
+
+// SetCacheEntries обновляет количество записей в кэше результатов
+func SetCacheEntries(entries int) {
+	SolveCacheEntries.Set(float64(entries))
+}
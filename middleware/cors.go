@@ -9,7 +9,7 @@ const csp = "default-src 'none'; script-src 'self'; connect-src 'self'; img-src
 
 func CorsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Methods", "POST,PUT,DELETE,GET")
+		w.Header().Set("Access-Control-Allow-Methods", "POST,PUT,PATCH,DELETE,GET")
 		w.Header().Set("Access-Control-Allow-Headers", "Authorization,Content-Type,X-Csrf-Token")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Expose-Headers", "Authorization,X-Csrf-Token")
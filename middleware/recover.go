@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"algo/apierror"
 	"algo/utils"
 )
 
@@ -14,7 +15,10 @@ func RecoverMiddleware(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				recoverLogger.Error(fmt.Sprintf("panic recovered: %v", err))
-				w.WriteHeader(http.StatusInternalServerError)
+				utils.WriteErrorBody(r.Context(), w, http.StatusInternalServerError, apierror.ErrorBody{
+					Code:    apierror.CodeInternal,
+					Message: "internal error",
+				})
 			}
 		}()
 
@@ -5,12 +5,15 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"algo/config"
+	"algo/metrics"
 	"github.com/gorilla/mux"
 	"github.com/satori/uuid"
 )
 
+// response запоминает код ответа для логов и метрик
 type response struct {
 	http.ResponseWriter
 	code int
@@ -21,13 +24,21 @@ func (resp *response) WriteHeader(code int) {
 	resp.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap позволяет http.ResponseController сбрасывать буфер ответа при потоковой передаче
+func (resp *response) Unwrap() http.ResponseWriter {
+	return resp.ResponseWriter
+}
+
 func CreateRequestIDMiddleware(logger *slog.Logger) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
 			reqID := uuid.NewV4().String()
 			reqIDLogger := logger.With(slog.String("x-request-id", reqID))
 
-			r = r.WithContext(context.WithValue(r.Context(), config.LoggerContextKey, reqIDLogger))
+			ctx := context.WithValue(r.Context(), config.LoggerContextKey, reqIDLogger)
+			ctx = context.WithValue(ctx, config.RequestIDContextKey, reqID)
+			r = r.WithContext(ctx)
 			resp := response{ResponseWriter: w}
 			resp.Header().Set("X-Request-ID", reqID)
 			resp.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -43,6 +54,8 @@ func CreateRequestIDMiddleware(logger *slog.Logger) mux.MiddlewareFunc {
 				resp.code = http.StatusOK
 			}
 
+			metrics.ObserveRequest(routeTemplate(r), r.Method, resp.code, time.Since(startTime))
+
 			reqIDLogger.
 				With(slog.String("method", r.Method)).
 				With(slog.String("uri", r.URL.Path)).
@@ -51,3 +64,18 @@ func CreateRequestIDMiddleware(logger *slog.Logger) mux.MiddlewareFunc {
 		})
 	}
 }
+
+// routeTemplate возвращает шаблон маршрута запроса, например /api/v2/mazes/{id:[0-9]+}
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return metrics.UnmatchedRoute
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return metrics.UnmatchedRoute
+	}
+
+	return template
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"algo/metrics"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestIDMiddlewareMetrics(t *testing.T) {
+	reqIDMiddleware := CreateRequestIDMiddleware(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	router := mux.NewRouter()
+	router.Use(reqIDMiddleware)
+	router.NotFoundHandler = reqIDMiddleware(http.NotFoundHandler())
+	router.Handle("/mazes/{id:[0-9]+}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	tests := []struct {
+		path   string
+		route  string
+		status string
+	}{
+		{path: "/mazes/1", route: "/mazes/{id:[0-9]+}", status: "200"},
+		{path: "/mazes/2", route: "/mazes/{id:[0-9]+}", status: "200"},
+		{path: "/mazes/0", route: "/mazes/{id:[0-9]+}", status: "400"},
+		{path: "/unknown/path", route: metrics.UnmatchedRoute, status: "404"},
+	}
+
+	for _, test := range tests {
+		counter := metrics.HTTPRequests.WithLabelValues(test.route, http.MethodGet, test.status)
+		before := testutil.ToFloat64(counter)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if recorder.Header().Get("X-Request-ID") == "" {
+			t.Errorf("%s: X-Request-ID is not set", test.path)
+		}
+		if got := testutil.ToFloat64(counter) - before; got != 1 {
+			t.Errorf("%s: counter for route=%s status=%s increased by %v, want 1", test.path, test.route, test.status, got)
+		}
+	}
+}
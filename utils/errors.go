@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"algo/apierror"
+	"algo/config"
+	"github.com/pkg/errors"
+)
+
+const msgInternalError = "internal error"
+
+func GetRequestIDFromContext(ctx context.Context) string {
+	if reqID, ok := ctx.Value(config.RequestIDContextKey).(string); ok {
+		return reqID
+	}
+
+	return ""
+}
+
+// ErrorBody возвращает тело ошибки для ответа. Ошибки, не являющиеся *apierror.APIError, считаются внутренними,
+// их текст клиенту не передается
+func ErrorBody(err error) (int, apierror.ErrorBody) {
+	var apiErr *apierror.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status, apiErr.Body()
+	}
+
+	return http.StatusInternalServerError, apierror.ErrorBody{Code: apierror.CodeInternal, Message: msgInternalError}
+}
+
+// WriteError логирует ошибку и отправляет клиенту JSON с ее кодом, описанием и идентификатором запроса
+func WriteError(ctx context.Context, w http.ResponseWriter, err error, msg string) {
+	LogError(ctx, err, msg)
+
+	status, body := ErrorBody(err)
+	WriteErrorBody(ctx, w, status, body)
+}
+
+// WriteErrorBody отправляет клиенту JSON с описанием ошибки
+func WriteErrorBody(ctx context.Context, w http.ResponseWriter, status int, body apierror.ErrorBody) {
+	body.RequestID = GetRequestIDFromContext(ctx)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(apierror.ErrorOutput{Error: body}); err != nil {
+		LogError(ctx, err, MsgErrMarshalResponse)
+	}
+}
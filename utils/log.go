@@ -10,10 +10,7 @@ import (
 )
 
 const (
-	Internal = "internal"
-	Invalid  = "invalid"
-
-	MsgErrMarshalResponse  = "failed to unmarshal request"
+	MsgErrMarshalResponse  = "failed to marshal response"
 	MsgErrUnmarshalRequest = "failed to unmarshal request"
 )
 